@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func newRegistry() *circuitbreaker.Registry {
+	return circuitbreaker.NewRegistry(circuitbreaker.Config{OpenTimeout: time.Minute})
+}
+
+func request(t *testing.T, h http.Handler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestStatusHandlerListsBreakers(t *testing.T) {
+	registry := newRegistry()
+	registry.Get("db")
+	registry.Get("cache").ForceOpen()
+
+	rec := request(t, StatusHandler(registry), http.MethodGet, "/status")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var statuses []circuitbreaker.BreakerStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// collectStatuses sorts by name.
+	if len(statuses) != 2 || statuses[0].Name != "cache" || statuses[1].Name != "db" {
+		t.Fatalf("statuses = %+v, want cache then db", statuses)
+	}
+	if statuses[0].State != circuitbreaker.StateOpen {
+		t.Fatalf("cache state = %v, want open", statuses[0].State)
+	}
+
+	if rec := request(t, StatusHandler(registry), http.MethodPost, "/status"); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST status = %d, want 405", rec.Code)
+	}
+}
+
+func TestActionHandlerRoutesThroughMux(t *testing.T) {
+	registry := newRegistry()
+	b := registry.Get("db")
+	h := Handler(registry)
+
+	if rec := request(t, h, http.MethodPost, "/breakers/db/force-open"); rec.Code != http.StatusNoContent {
+		t.Fatalf("force-open = %d, want 204", rec.Code)
+	}
+	if got := b.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+	if rec := request(t, h, http.MethodPost, "/breakers/db/reset"); rec.Code != http.StatusNoContent {
+		t.Fatalf("reset = %d, want 204", rec.Code)
+	}
+	if got := b.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed", got)
+	}
+
+	// Actions never create breakers, and unknown actions are rejected.
+	if rec := request(t, h, http.MethodPost, "/breakers/ghost/reset"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown breaker = %d, want 404", rec.Code)
+	}
+	if rec := request(t, h, http.MethodPost, "/breakers/db/explode"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown action = %d, want 400", rec.Code)
+	}
+	if _, ok := registry.Lookup("ghost"); ok {
+		t.Fatal("action against unknown breaker created it")
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	registry := newRegistry()
+	registry.Get("db")
+	registry.Get("cache")
+
+	if rec := request(t, HealthHandler(registry), http.MethodGet, "/healthz"); rec.Code != http.StatusOK {
+		t.Fatalf("all closed = %d, want 200", rec.Code)
+	}
+
+	// One open breaker degrades the registry: still 200 by default, 503
+	// with WithFailOnDegraded.
+	registry.Get("cache").ForceOpen()
+	if rec := request(t, HealthHandler(registry), http.MethodGet, "/healthz"); rec.Code != http.StatusOK {
+		t.Fatalf("degraded = %d, want 200", rec.Code)
+	}
+	rec := request(t, HealthHandler(registry, WithFailOnDegraded()), http.MethodGet, "/healthz")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("degraded with WithFailOnDegraded = %d, want 503", rec.Code)
+	}
+
+	// A critical breaker being open is unhealthy regardless.
+	rec = request(t, HealthHandler(registry, WithCritical("cache")), http.MethodGet, "/healthz")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("critical open = %d, want 503", rec.Code)
+	}
+	var report struct {
+		Status string   `json:"status"`
+		Open   []string `json:"open"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.Status != "unhealthy" {
+		t.Fatalf("status = %q, want unhealthy", report.Status)
+	}
+}
+
+func TestDashboardEscapesAndAvoidsInlineHandlers(t *testing.T) {
+	rec := request(t, DashboardHandler(), http.MethodGet, "/")
+	body := rec.Body.String()
+	if !strings.Contains(body, "function esc(") {
+		t.Fatal("dashboard is missing the esc() helper; breaker names would be injected unescaped")
+	}
+	if strings.Contains(body, "onclick=") {
+		t.Fatal("dashboard builds inline onclick handlers from breaker names")
+	}
+	if !strings.Contains(body, "addEventListener('click'") {
+		t.Fatal("dashboard does not delegate actions via addEventListener")
+	}
+}
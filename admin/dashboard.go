@@ -0,0 +1,153 @@
+package admin
+
+import (
+	"net/http"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Handler returns the combined admin handler:
+//
+//	GET  /                          — HTML dashboard
+//	GET  /status                    — JSON status (see StatusHandler)
+//	POST /breakers/{name}/{action}  — action is force-open, force-close or reset
+//
+// Mount it under a prefix with http.StripPrefix:
+//
+//	mux.Handle("/circuitbreaker/", http.StripPrefix("/circuitbreaker", admin.Handler(registry)))
+func Handler(registry *circuitbreaker.Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /status", StatusHandler(registry))
+	mux.Handle("POST /breakers/{name}/{action}", ActionHandler(registry))
+	mux.Handle("GET /", DashboardHandler())
+	return mux
+}
+
+// ActionHandler returns a handler applying admin actions to single
+// breakers. It responds 404 for unknown breakers — actions never create
+// breakers — and 400 for unknown actions.
+func ActionHandler(registry *circuitbreaker.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := registry.Lookup(r.PathValue("name"))
+		if !ok {
+			http.Error(w, "unknown breaker", http.StatusNotFound)
+			return
+		}
+		switch r.PathValue("action") {
+		case "force-open":
+			b.ForceOpen()
+		case "force-close":
+			b.ForceClose()
+		case "reset":
+			b.Reset()
+		default:
+			http.Error(w, "unknown action", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// DashboardHandler serves the embedded HTML dashboard. The page polls the
+// sibling status endpoint and posts to the sibling action endpoints, so it
+// works under any mount prefix without configuration.
+func DashboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(dashboardHTML))
+	})
+}
+
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Circuit Breakers</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; background: #fafafa; color: #222; }
+  h1 { font-size: 1.3rem; }
+  table { border-collapse: collapse; width: 100%; background: #fff; }
+  th, td { padding: .5rem .75rem; border-bottom: 1px solid #e5e5e5; text-align: left; }
+  th { background: #f0f0f0; font-weight: 600; }
+  .state { padding: .15rem .5rem; border-radius: .25rem; color: #fff; font-size: .85rem; }
+  .state.closed { background: #2e8b57; }
+  .state.half-open { background: #e0a800; }
+  .state.open { background: #c0392b; }
+  button { margin-right: .25rem; padding: .2rem .5rem; cursor: pointer; }
+  svg.spark { vertical-align: middle; }
+  #error { color: #c0392b; }
+</style>
+</head>
+<body>
+<h1>Circuit Breakers</h1>
+<p id="error"></p>
+<table>
+  <thead><tr>
+    <th>Name</th><th>State</th><th>In state</th><th>Requests</th>
+    <th>Failure rate</th><th>Rejections</th><th>Actions</th>
+  </tr></thead>
+  <tbody id="rows"></tbody>
+</table>
+<script>
+const history = {};               // name -> recent failure rates
+const HISTORY_LEN = 40;
+
+// Breaker names can derive from raw request paths (cbhttp per-path and
+// per-host keys), so treat them as untrusted and escape everything
+// interpolated into markup.
+function esc(s) {
+  return String(s).replace(/[&<>"']/g, c => ({
+    '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;'
+  }[c]));
+}
+
+function spark(values) {
+  if (!values || values.length < 2) return '';
+  const w = 100, h = 20;
+  const pts = values.map((v, i) =>
+    (i * w / (HISTORY_LEN - 1)).toFixed(1) + ',' + (h - v * (h - 2) - 1).toFixed(1)
+  ).join(' ');
+  return '<svg class="spark" width="' + w + '" height="' + h + '">' +
+    '<polyline fill="none" stroke="#c0392b" stroke-width="1.5" points="' + pts + '"/></svg>';
+}
+
+function act(name, action) {
+  fetch('breakers/' + encodeURIComponent(name) + '/' + action, { method: 'POST' })
+    .then(refresh);
+}
+
+function refresh() {
+  fetch('status').then(r => r.json()).then(breakers => {
+    document.getElementById('error').textContent = '';
+    const rows = breakers.map(b => {
+      const hist = history[b.name] = (history[b.name] || []).concat([b.failure_rate]).slice(-HISTORY_LEN);
+      return '<tr data-name="' + esc(b.name) + '">' +
+        '<td>' + esc(b.name) + '</td>' +
+        '<td><span class="state ' + esc(b.state) + '">' + esc(b.state) + '</span></td>' +
+        '<td>' + esc(b.time_in_state) + '</td>' +
+        '<td>' + b.counts.Requests + '</td>' +
+        '<td>' + (b.failure_rate * 100).toFixed(1) + '% ' + spark(hist) + '</td>' +
+        '<td>' + b.rejections + '</td>' +
+        '<td>' +
+          '<button data-action="force-open">open</button>' +
+          '<button data-action="force-close">close</button>' +
+          '<button data-action="reset">reset</button>' +
+        '</td></tr>';
+    });
+    document.getElementById('rows').innerHTML = rows.join('');
+  }).catch(err => {
+    document.getElementById('error').textContent = 'status fetch failed: ' + err;
+  });
+}
+
+document.getElementById('rows').addEventListener('click', e => {
+  const action = e.target.dataset.action;
+  if (action) act(e.target.closest('tr').dataset.name, action);
+});
+
+refresh();
+setInterval(refresh, 2000);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// DebugHandler returns a pprof-style plaintext handler dumping every
+// breaker's full internal state — generation, expiry, window buckets,
+// pending notifications — for troubleshooting without a debugger. Mount it
+// under /debug/circuitbreaker next to /debug/pprof; the format is for
+// humans and may change between releases.
+func DebugHandler(registry *circuitbreaker.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		breakers := registry.All()
+		sort.Slice(breakers, func(i, j int) bool { return breakers[i].Name() < breakers[j].Name() })
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "circuitbreaker: %d breakers\n", len(breakers))
+		for _, b := range breakers {
+			writeDebugState(w, b.DebugState())
+		}
+	})
+}
+
+func writeDebugState(w http.ResponseWriter, d circuitbreaker.DebugState) {
+	fmt.Fprintf(w, "\nbreaker %q\n", d.Name)
+	fmt.Fprintf(w, "  state: %s (since %s, forced=%v)\n", d.State, d.StateSince.Format("2006-01-02T15:04:05.000Z07:00"), d.Forced)
+	fmt.Fprintf(w, "  generation: %d", d.Generation)
+	if d.Expiry.IsZero() {
+		fmt.Fprintf(w, " (no expiry)\n")
+	} else {
+		fmt.Fprintf(w, " (expires %s)\n", d.Expiry.Format("2006-01-02T15:04:05.000Z07:00"))
+	}
+	fmt.Fprintf(w, "  counts: requests=%d successes=%d failures=%d consec_successes=%d consec_failures=%d\n",
+		d.Counts.Requests, d.Counts.TotalSuccesses, d.Counts.TotalFailures,
+		d.Counts.ConsecutiveSuccesses, d.Counts.ConsecutiveFailures)
+	fmt.Fprintf(w, "  rejections: %d  opens: %d\n", d.Rejections, d.Opens)
+	fmt.Fprintf(w, "  config: max_half_open=%d open_timeout=%s interval=%s\n",
+		d.MaxHalfOpenRequests, d.OpenTimeout, d.Interval)
+	if d.WindowBuckets != nil {
+		fmt.Fprintf(w, "  window: size=%s buckets=%d current=%d\n", d.WindowSize, len(d.WindowBuckets), d.WindowCurrent)
+		for i, bucket := range d.WindowBuckets {
+			marker := " "
+			if i == d.WindowCurrent {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "   %s[%d] requests=%d successes=%d failures=%d\n",
+				marker, i, bucket.Requests, bucket.TotalSuccesses, bucket.TotalFailures)
+		}
+	}
+	fmt.Fprintf(w, "  pending_events: %d  history: %d  failure_samples: %d\n",
+		d.PendingEvents, d.HistoryLen, d.FailureSamples)
+}
@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// EventStream broadcasts breaker events to dashboard clients over
+// Server-Sent Events, so they update in real time instead of polling.
+// Two event types are emitted, both JSON:
+//
+//	state_change — {"breaker", "from", "to", "at"}
+//	rejections   — {"counts": {breaker: n}, "at"} once per interval while
+//	               requests are being rejected
+//
+// To observe rejections, wire the stream into the breakers' Metrics hook
+// (it implements circuitbreaker.MetricsCollector), typically via
+// circuitbreaker.MultiCollector alongside the real metrics backend.
+type EventStream struct {
+	mu         sync.Mutex
+	subs       map[chan []byte]struct{}
+	rejections map[string]uint64
+}
+
+// NewEventStream returns a stream attached to registry's state changes.
+// The rejection aggregation goroutine stops when registry.Shutdown runs.
+func NewEventStream(registry *circuitbreaker.Registry) *EventStream {
+	s := &EventStream{
+		subs:       make(map[chan []byte]struct{}),
+		rejections: make(map[string]uint64),
+	}
+	registry.OnStateChange(func(name string, from, to circuitbreaker.State) {
+		s.publish("state_change", map[string]any{
+			"breaker": name,
+			"from":    from.String(),
+			"to":      to.String(),
+			"at":      time.Now(),
+		})
+	})
+	stop := make(chan struct{})
+	go s.flushRejections(stop)
+	registry.AddShutdownHook(func(context.Context) error {
+		close(stop)
+		return nil
+	})
+	return s
+}
+
+// Handler returns the SSE endpoint.
+func (s *EventStream) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+
+		events := s.subscribe()
+		defer s.unsubscribe(events)
+		flusher.Flush()
+		for {
+			select {
+			case event := <-events:
+				_, _ = w.Write(event)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+}
+
+// RecordSuccess implements circuitbreaker.MetricsCollector.
+func (s *EventStream) RecordSuccess(string, time.Duration) {}
+
+// RecordFailure implements circuitbreaker.MetricsCollector.
+func (s *EventStream) RecordFailure(string, time.Duration) {}
+
+// RecordRejection implements circuitbreaker.MetricsCollector; rejections
+// are aggregated and emitted in bursts rather than per event.
+func (s *EventStream) RecordRejection(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejections[name]++
+}
+
+// RecordStateChange implements circuitbreaker.MetricsCollector; state
+// changes already arrive through the registry listener.
+func (s *EventStream) RecordStateChange(string, circuitbreaker.State, circuitbreaker.State) {}
+
+func (s *EventStream) subscribe() chan []byte {
+	events := make(chan []byte, 16)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs[events] = struct{}{}
+	return events
+}
+
+func (s *EventStream) unsubscribe(events chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subs, events)
+}
+
+func (s *EventStream) publish(event string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	frame := []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", event, data))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		select {
+		case sub <- frame:
+		default: // drop for slow consumers rather than blocking the breaker
+		}
+	}
+}
+
+func (s *EventStream) flushRejections(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			counts := s.rejections
+			if len(counts) > 0 {
+				s.rejections = make(map[string]uint64)
+			}
+			s.mu.Unlock()
+			if len(counts) > 0 {
+				s.publish("rejections", map[string]any{
+					"counts": counts,
+					"at":     time.Now(),
+				})
+			}
+		case <-stop:
+			return
+		}
+	}
+}
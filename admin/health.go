@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// HealthOption configures HealthHandler.
+type HealthOption func(*healthOptions)
+
+type healthOptions struct {
+	failOnDegraded bool
+	critical       []string
+}
+
+// WithFailOnDegraded makes the handler return 503 for a degraded registry
+// too, not just an unhealthy one — for liveness-style probes that should
+// only pass when everything is closed.
+func WithFailOnDegraded() HealthOption {
+	return func(o *healthOptions) { o.failOnDegraded = true }
+}
+
+// WithCritical marks the named breakers as critical: while any of them is
+// open the handler reports unhealthy. It is shorthand for adding a
+// circuitbreaker.CriticalOpen rule to the registry's health rules.
+func WithCritical(names ...string) HealthOption {
+	return func(o *healthOptions) { o.critical = append(o.critical, names...) }
+}
+
+// HealthHandler returns a /healthz-style handler deriving readiness from
+// the registry's breaker states. Healthy and degraded registries respond
+// 200 (degraded services can still do useful work), unhealthy ones 503;
+// the body carries per-breaker detail either way.
+func HealthHandler(registry *circuitbreaker.Registry, opts ...HealthOption) http.Handler {
+	var options healthOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var critical circuitbreaker.HealthRule
+	if len(options.critical) > 0 {
+		critical = circuitbreaker.CriticalOpen(options.critical...)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := registry.Health()
+		if critical != nil {
+			if status := critical(registry.All()); status > report.Status {
+				report.Status = status
+			}
+		}
+
+		code := http.StatusOK
+		if report.Status == circuitbreaker.Unhealthy ||
+			(options.failOnDegraded && report.Status == circuitbreaker.Degraded) {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(struct {
+			Status   string                         `json:"status"`
+			Open     []string                       `json:"open,omitempty"`
+			Breakers []circuitbreaker.BreakerStatus `json:"breakers"`
+		}{
+			Status:   report.Status.String(),
+			Open:     report.Open,
+			Breakers: collectStatuses(registry),
+		})
+	})
+}
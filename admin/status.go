@@ -0,0 +1,40 @@
+// Package admin provides HTTP handlers for inspecting and operating the
+// circuit breakers of a registry: JSON status, health checks and a small
+// dashboard. Mount the handlers on an internal mux; they perform no
+// authentication themselves.
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// StatusHandler returns a handler rendering every breaker in the registry
+// as JSON (circuitbreaker.BreakerStatus), for curl-based inspection and
+// status pages.
+func StatusHandler(registry *circuitbreaker.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		statuses := collectStatuses(registry)
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(statuses)
+	})
+}
+
+func collectStatuses(registry *circuitbreaker.Registry) []circuitbreaker.BreakerStatus {
+	breakers := registry.All()
+	statuses := make([]circuitbreaker.BreakerStatus, 0, len(breakers))
+	for _, b := range breakers {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,32 @@
+package circuitbreaker
+
+// AttributeRecorder is an optional interface a MetricsCollector may
+// implement to learn a breaker's resource attributes (Config.Attributes)
+// when the breaker is created. Backends turn the attributes into metric
+// labels or span attributes so alerts can be routed by ownership.
+type AttributeRecorder interface {
+	RecordAttributes(name string, attrs map[string]string)
+}
+
+// Attributes returns a copy of the breaker's resource attributes, or nil if
+// none were configured.
+func (b *Breaker) Attributes() map[string]string {
+	if len(b.cfg.Attributes) == 0 {
+		return nil
+	}
+	attrs := make(map[string]string, len(b.cfg.Attributes))
+	for k, v := range b.cfg.Attributes {
+		attrs[k] = v
+	}
+	return attrs
+}
+
+// RecordAttributes forwards to the collectors implementing
+// AttributeRecorder.
+func (m multiCollector) RecordAttributes(name string, attrs map[string]string) {
+	for _, c := range m {
+		if ar, ok := c.(AttributeRecorder); ok {
+			ar.RecordAttributes(name, attrs)
+		}
+	}
+}
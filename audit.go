@@ -0,0 +1,38 @@
+package circuitbreaker
+
+import "time"
+
+const defaultHistorySize = 10
+
+// TransitionRecord is one entry in a breaker's transition history: when it
+// changed state, between which states, and the counts that triggered the
+// change. Error carries the message of the failure that tripped the
+// breaker, when known.
+type TransitionRecord struct {
+	At     time.Time `json:"at"`
+	From   State     `json:"from"`
+	To     State     `json:"to"`
+	Counts Counts    `json:"counts"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// History returns the breaker's most recent state transitions, oldest
+// first. The history is a bounded ring (Config.HistorySize entries), kept
+// in memory so post-incident analysis doesn't depend on scraping logs.
+func (b *Breaker) History() []TransitionRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	history := make([]TransitionRecord, len(b.history))
+	copy(history, b.history)
+	return history
+}
+
+// recordHistory appends a transition record, evicting the oldest entry
+// once the ring is full. Callers must hold b.mu.
+func (b *Breaker) recordHistory(record TransitionRecord) {
+	size := b.cfg.HistorySize
+	if len(b.history) >= size {
+		b.history = append(b.history[:0], b.history[len(b.history)-size+1:]...)
+	}
+	b.history = append(b.history, record)
+}
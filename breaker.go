@@ -0,0 +1,513 @@
+// Package circuitbreaker implements the circuit breaker pattern: it tracks
+// the outcomes of requests against a dependency and fails fast once the
+// dependency looks unhealthy, giving it room to recover.
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when the breaker is open and rejects a request
+// without attempting it.
+var ErrOpen = errors.New("circuitbreaker: circuit is open")
+
+// ErrTooManyRequests is returned when the breaker is half-open and the
+// allowed number of probe requests is already in flight.
+var ErrTooManyRequests = errors.New("circuitbreaker: too many requests in half-open state")
+
+// Breaker is a circuit breaker guarding a single dependency. It is safe for
+// concurrent use.
+type Breaker struct {
+	cfg Config
+
+	mu         sync.Mutex
+	state      State
+	forced     bool // state was pinned by ForceOpen/ForceClose
+	generation uint64
+	counts     Counts
+	rejections uint64    // requests rejected without being attempted
+	expiry     time.Time // when the current generation ends
+	tripCounts Counts    // counts at the moment the breaker last tripped
+	stateSince time.Time // when the breaker entered its current state
+
+	history   []TransitionRecord // bounded ring of recent transitions
+	window    *slidingWindow     // non-nil when Config.WindowSize > 0
+	opens     uint64             // times the breaker has entered StateOpen
+	failures  []FailureSample    // bounded reservoir of recent failures
+	lastError string             // message of the most recent failure
+
+	// pending holds transitions made under mu but not yet announced;
+	// notifications fire after the lock is released so listeners can call
+	// back into the breaker safely.
+	pending []transition
+}
+
+type transition struct {
+	from, to State
+}
+
+// New returns a Breaker configured by cfg. Zero fields of cfg are filled
+// with defaults; see Config.
+func New(cfg Config) *Breaker {
+	now := time.Now()
+	b := &Breaker{cfg: cfg.withDefaults(), stateSince: now}
+	if b.cfg.WindowSize > 0 {
+		b.window = newSlidingWindow(b.cfg.WindowSize, b.cfg.WindowBuckets, now)
+	}
+	b.toNewGeneration(now)
+	if ar, ok := b.cfg.Metrics.(AttributeRecorder); ok && len(b.cfg.Attributes) > 0 {
+		ar.RecordAttributes(b.cfg.Name, b.Attributes())
+	}
+	return b
+}
+
+// Name returns the breaker's configured name.
+func (b *Breaker) Name() string { return b.cfg.Name }
+
+// State returns the breaker's current state.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	state, _ := b.currentState(time.Now())
+	pending := b.takePending()
+	b.mu.Unlock()
+	b.announce(pending)
+	return state
+}
+
+// Rejections returns the total number of requests this breaker has
+// rejected over its lifetime, i.e. requests that never reached the
+// dependency because the circuit was open or the half-open probe budget was
+// exhausted.
+func (b *Breaker) Rejections() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rejections
+}
+
+// Counts returns a snapshot of the current generation's counts.
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.counts
+}
+
+// StateSince returns when the breaker entered its current state.
+func (b *Breaker) StateSince() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stateSince
+}
+
+// TripCounts returns the counts as they stood the last time the breaker
+// tripped open, which is the reason it tripped. The zero Counts is
+// returned if the breaker has never tripped.
+func (b *Breaker) TripCounts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tripCounts
+}
+
+// Do runs fn if the breaker allows it and records the outcome. It returns
+// ErrOpen or ErrTooManyRequests without calling fn when the request is
+// rejected; otherwise it returns fn's error.
+func (b *Breaker) Do(fn func() error) error {
+	return b.DoContext(context.Background(), func(context.Context) error { return fn() })
+}
+
+// DoContext is Do with a request context, which is forwarded to fn and to
+// context-aware metrics collectors (see ContextRecorder).
+func (b *Breaker) DoContext(ctx context.Context, fn func(ctx context.Context) error) error {
+	done, err := b.AllowContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			done(errors.New("circuitbreaker: panic in request"))
+			panic(r)
+		}
+	}()
+	err = fn(ctx)
+	done(err)
+	return err
+}
+
+// Execute runs fn through b and returns its result. It is a generic
+// convenience wrapper around Breaker.Do.
+func Execute[T any](b *Breaker, fn func() (T, error)) (T, error) {
+	var result T
+	err := b.Do(func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+// Allow reports whether a request may proceed. On success it returns a done
+// callback that must be invoked exactly once with the request's resulting
+// error (nil for success). On rejection it returns ErrOpen or
+// ErrTooManyRequests and a nil callback.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	return b.AllowContext(context.Background())
+}
+
+// AllowContext is Allow with a request context, forwarded to context-aware
+// metrics collectors (see ContextRecorder).
+func (b *Breaker) AllowContext(ctx context.Context) (done func(err error), err error) {
+	generation, err := b.beforeRequest()
+	if err != nil {
+		reason := ReasonOpen
+		if errors.Is(err, ErrTooManyRequests) {
+			reason = ReasonTooManyRequests
+		}
+		recordRejection(b.cfg.Metrics, b.cfg.Name, reason)
+		return nil, err
+	}
+	start := time.Now()
+	return func(err error) {
+		success := b.cfg.IsSuccessful(err)
+		if b.cfg.Metrics != nil {
+			cr, contextAware := b.cfg.Metrics.(ContextRecorder)
+			switch {
+			case success && contextAware:
+				cr.RecordSuccessContext(ctx, b.cfg.Name, time.Since(start))
+			case success:
+				b.cfg.Metrics.RecordSuccess(b.cfg.Name, time.Since(start))
+			case contextAware:
+				cr.RecordFailureContext(ctx, b.cfg.Name, time.Since(start))
+			default:
+				b.cfg.Metrics.RecordFailure(b.cfg.Name, time.Since(start))
+			}
+			if !success {
+				if fc, ok := b.cfg.Metrics.(FailureCategoryRecorder); ok {
+					fc.RecordFailureCategory(b.cfg.Name, b.cfg.ClassifyError(err))
+				}
+			}
+		}
+		b.afterRequest(generation, success, err)
+	}, nil
+}
+
+// Record registers the outcome of a request that was not admitted through
+// Allow, for integrations that only learn which breaker a call belongs to
+// after it completed (see cbgrpc.WithPeerBreakers). The outcome counts
+// exactly like an allowed request's — including as probe evidence while
+// half-open — but consumes no separate admission, and outcomes arriving
+// while the breaker is open are discarded. Record updates state and
+// counts only; it does not fire per-request metrics, since it has no
+// admission timestamp to measure a duration from.
+func (b *Breaker) Record(err error) {
+	success := b.cfg.IsSuccessful(err)
+	b.mu.Lock()
+	defer func() {
+		pending := b.takePending()
+		b.mu.Unlock()
+		b.announce(pending)
+	}()
+
+	now := time.Now()
+	state, _ := b.currentState(now)
+	if state == StateOpen {
+		return
+	}
+	b.counts.onRequest()
+	if b.window != nil {
+		b.window.onRequest(now)
+	}
+	if success {
+		b.onSuccess(state, now)
+		return
+	}
+	b.recordFailureSample(err, now)
+	weight := uint64(1)
+	if b.cfg.FailureWeight != nil {
+		weight = b.cfg.FailureWeight(err)
+	}
+	b.onFailure(state, now, weight)
+}
+
+// ForceOpen pins the breaker open. Requests are rejected until ForceClose
+// or Reset is called; outcomes recorded in the meantime are ignored.
+func (b *Breaker) ForceOpen() {
+	b.mu.Lock()
+	b.forced = true
+	b.setState(StateOpen, time.Now())
+	pending := b.takePending()
+	b.mu.Unlock()
+	b.announce(pending)
+}
+
+// ForceClose pins the breaker closed. Requests flow through and outcomes
+// are counted, but the breaker will not trip until Reset is called.
+func (b *Breaker) ForceClose() {
+	b.mu.Lock()
+	b.forced = true
+	b.setState(StateClosed, time.Now())
+	pending := b.takePending()
+	b.mu.Unlock()
+	b.announce(pending)
+}
+
+// HoldOpen extends the breaker's open period to at least d from now, so
+// an explicit backpressure signal from the dependency — an HTTP
+// Retry-After, a gRPC RetryInfo — overrides the static OpenTimeout. It
+// does nothing unless the breaker is currently open, and never shortens
+// the remaining open period.
+func (b *Breaker) HoldOpen(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != StateOpen || b.forced {
+		return
+	}
+	if expiry := time.Now().Add(d); expiry.After(b.expiry) {
+		b.expiry = expiry
+	}
+}
+
+// UntilHalfOpen returns how long the breaker will keep rejecting requests
+// before it transitions to half-open — the right value for a Retry-After
+// header. It returns 0 when the breaker is not open, and 0 for a breaker
+// pinned open with ForceOpen, which has no scheduled transition.
+func (b *Breaker) UntilHalfOpen() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != StateOpen || b.forced {
+		return 0
+	}
+	if wait := time.Until(b.expiry); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Reset clears any forced state and returns the breaker to closed with
+// fresh counts.
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	b.forced = false
+	if b.state == StateClosed {
+		b.toNewGeneration(time.Now())
+	} else {
+		b.setState(StateClosed, time.Now())
+	}
+	pending := b.takePending()
+	b.mu.Unlock()
+	b.announce(pending)
+}
+
+func (b *Breaker) beforeRequest() (uint64, error) {
+	b.mu.Lock()
+	defer func() {
+		pending := b.takePending()
+		b.mu.Unlock()
+		b.announce(pending)
+	}()
+
+	now := time.Now()
+	state, generation := b.currentState(now)
+
+	switch {
+	case state == StateOpen:
+		b.rejections++
+		return generation, ErrOpen
+	case state == StateHalfOpen && b.counts.Requests >= b.cfg.MaxHalfOpenRequests:
+		b.rejections++
+		return generation, ErrTooManyRequests
+	}
+	b.counts.onRequest()
+	if b.window != nil {
+		b.window.onRequest(now)
+	}
+	if state == StateHalfOpen {
+		if pr, ok := b.cfg.Metrics.(ProbeRecorder); ok {
+			pr.RecordProbeAttempt(b.cfg.Name)
+		}
+	}
+	return generation, nil
+}
+
+func (b *Breaker) afterRequest(generation uint64, success bool, err error) {
+	b.mu.Lock()
+	defer func() {
+		pending := b.takePending()
+		b.mu.Unlock()
+		b.announce(pending)
+	}()
+
+	now := time.Now()
+	state, current := b.currentState(now)
+	if generation != current {
+		return // outcome belongs to a previous generation
+	}
+	if success {
+		b.onSuccess(state, now)
+	} else {
+		b.recordFailureSample(err, now)
+		weight := uint64(1)
+		if b.cfg.FailureWeight != nil {
+			weight = b.cfg.FailureWeight(err)
+		}
+		b.onFailure(state, now, weight)
+	}
+}
+
+func (b *Breaker) onSuccess(state State, now time.Time) {
+	if b.window != nil {
+		b.window.onSuccess(now)
+	}
+	switch state {
+	case StateClosed:
+		b.counts.onSuccess()
+	case StateHalfOpen:
+		b.counts.onSuccess()
+		b.recordProbeResult(true)
+		if b.counts.ConsecutiveSuccesses >= b.cfg.MaxHalfOpenRequests {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+// evaluatedCounts returns the Counts the trip condition sees: the live
+// generation counts, or — when a sliding window is configured — the
+// window's totals with the live consecutive counts. Callers must hold
+// b.mu.
+func (b *Breaker) evaluatedCounts(now time.Time) Counts {
+	if b.window == nil {
+		return b.counts
+	}
+	counts := b.window.aggregate(now)
+	counts.ConsecutiveSuccesses = b.counts.ConsecutiveSuccesses
+	counts.ConsecutiveFailures = b.counts.ConsecutiveFailures
+	return counts
+}
+
+// WindowCounts returns the totals over the breaker's sliding window, or
+// false if no window is configured. This is exactly what the trip decision
+// evaluates; the prom package exports it via NewWindowCollector.
+func (b *Breaker) WindowCounts() (Counts, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.window == nil {
+		return Counts{}, false
+	}
+	return b.evaluatedCounts(time.Now()), true
+}
+
+// recordProbeResult reports a half-open probe outcome to the metrics
+// collector, if it cares. Callers must hold b.mu.
+func (b *Breaker) recordProbeResult(success bool) {
+	if pr, ok := b.cfg.Metrics.(ProbeRecorder); ok {
+		pr.RecordProbeResult(b.cfg.Name, success)
+	}
+}
+
+func (b *Breaker) onFailure(state State, now time.Time, weight uint64) {
+	if b.window != nil {
+		b.window.onFailure(now, weight)
+	}
+	switch state {
+	case StateClosed:
+		b.counts.onWeightedFailure(weight)
+		evaluated := b.evaluatedCounts(now)
+		if !b.forced && b.cfg.ReadyToTrip(evaluated) {
+			b.tripCounts = evaluated
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.counts.onWeightedFailure(weight)
+		b.recordProbeResult(false)
+		b.tripCounts = b.counts
+		b.setState(StateOpen, now)
+	}
+}
+
+// currentState resolves the state at now, transitioning an expired open
+// breaker to half-open or rolling the closed-state counts over to a new
+// generation. Callers must hold b.mu.
+func (b *Breaker) currentState(now time.Time) (State, uint64) {
+	switch b.state {
+	case StateClosed:
+		if !b.expiry.IsZero() && b.expiry.Before(now) {
+			b.toNewGeneration(now)
+		}
+	case StateOpen:
+		if !b.forced && b.expiry.Before(now) {
+			b.setState(StateHalfOpen, now)
+		}
+	}
+	return b.state, b.generation
+}
+
+// setState transitions the breaker and starts a new generation. The
+// transition is queued on b.pending; the public entry point that triggered
+// it announces it once the lock is released. Callers must hold b.mu.
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	record := TransitionRecord{
+		At:     now,
+		From:   from,
+		To:     state,
+		Counts: b.counts,
+	}
+	if state == StateOpen && !b.forced {
+		record.Error = b.lastError // the failure that tripped the breaker
+	}
+	b.recordHistory(record)
+	b.state = state
+	b.stateSince = now
+	if state == StateOpen {
+		b.opens++
+	}
+	b.toNewGeneration(now)
+	b.pending = append(b.pending, transition{from: from, to: state})
+}
+
+// takePending removes and returns the queued transitions. Callers must
+// hold b.mu.
+func (b *Breaker) takePending() []transition {
+	pending := b.pending
+	b.pending = nil
+	return pending
+}
+
+// announce fires the metrics collector and OnStateChange hook for each
+// transition, outside the breaker's lock so hooks may call back into it.
+func (b *Breaker) announce(pending []transition) {
+	for _, t := range pending {
+		if b.cfg.Metrics != nil {
+			b.cfg.Metrics.RecordStateChange(b.cfg.Name, t.from, t.to)
+		}
+		if b.cfg.OnStateChange != nil {
+			b.cfg.OnStateChange(b.cfg.Name, t.from, t.to)
+		}
+	}
+}
+
+// toNewGeneration resets the counts and computes the new generation's
+// expiry. Callers must hold b.mu.
+func (b *Breaker) toNewGeneration(now time.Time) {
+	b.generation++
+	b.counts.clear()
+	if b.window != nil {
+		b.window.reset(now)
+	}
+	switch b.state {
+	case StateClosed:
+		if b.cfg.Interval > 0 {
+			b.expiry = now.Add(b.cfg.Interval)
+		} else {
+			b.expiry = time.Time{}
+		}
+	case StateOpen:
+		b.expiry = now.Add(b.cfg.OpenTimeout)
+	default:
+		b.expiry = time.Time{}
+	}
+}
@@ -0,0 +1,217 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFail = errors.New("boom")
+
+// tripAfter returns a config that opens the circuit on the n-th
+// consecutive failure, with a short open timeout for tests that wait out
+// the open state.
+func tripAfter(n uint64) Config {
+	return Config{
+		Name:        "test",
+		OpenTimeout: 25 * time.Millisecond,
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= n },
+	}
+}
+
+func fail(b *Breaker) error    { return b.Do(func() error { return errFail }) }
+func succeed(b *Breaker) error { return b.Do(func() error { return nil }) }
+
+func TestBreakerTripsAfterConsecutiveFailures(t *testing.T) {
+	b := New(tripAfter(3))
+	for i := 0; i < 2; i++ {
+		fail(b)
+		if got := b.State(); got != StateClosed {
+			t.Fatalf("state after %d failures = %v, want closed", i+1, got)
+		}
+	}
+	fail(b)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after 3 failures = %v, want open", got)
+	}
+
+	called := false
+	err := b.Do(func() error { called = true; return nil })
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("Do while open = %v, want ErrOpen", err)
+	}
+	if called {
+		t.Fatal("fn was called while the breaker was open")
+	}
+	if got := b.Rejections(); got != 1 {
+		t.Fatalf("Rejections = %d, want 1", got)
+	}
+}
+
+func TestBreakerSuccessResetsConsecutiveFailures(t *testing.T) {
+	b := New(tripAfter(3))
+	fail(b)
+	fail(b)
+	succeed(b)
+	fail(b)
+	fail(b)
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state = %v, want closed (streak was broken)", got)
+	}
+	fail(b)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+}
+
+func TestBreakerHalfOpenProbeClosesCircuit(t *testing.T) {
+	b := New(tripAfter(1))
+	fail(b)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state after timeout = %v, want half-open", got)
+	}
+	if err := succeed(b); err != nil {
+		t.Fatalf("probe returned %v", err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state after successful probe = %v, want closed", got)
+	}
+}
+
+func TestBreakerHalfOpenFailureReopens(t *testing.T) {
+	b := New(tripAfter(1))
+	fail(b)
+	time.Sleep(30 * time.Millisecond)
+	fail(b)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after failed probe = %v, want open", got)
+	}
+}
+
+func TestBreakerHalfOpenProbeBudget(t *testing.T) {
+	b := New(tripAfter(1))
+	fail(b)
+	time.Sleep(30 * time.Millisecond)
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("first probe rejected: %v", err)
+	}
+	if _, err := b.Allow(); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("second concurrent probe = %v, want ErrTooManyRequests", err)
+	}
+	done(nil)
+}
+
+func TestBreakerForceOpenAndReset(t *testing.T) {
+	b := New(tripAfter(3))
+	b.ForceOpen()
+	if err := succeed(b); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Do while forced open = %v, want ErrOpen", err)
+	}
+	// A forced breaker ignores the open timeout.
+	time.Sleep(30 * time.Millisecond)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after timeout while forced = %v, want open", got)
+	}
+	b.Reset()
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state after Reset = %v, want closed", got)
+	}
+	if err := succeed(b); err != nil {
+		t.Fatalf("Do after Reset = %v", err)
+	}
+}
+
+func TestBreakerStaleOutcomeIgnored(t *testing.T) {
+	b := New(tripAfter(1))
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	b.Reset() // new generation; the in-flight outcome is now stale
+	done(errFail)
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state = %v, want closed (stale failure must not trip)", got)
+	}
+	if got := b.Counts(); got.TotalFailures != 0 {
+		t.Fatalf("TotalFailures = %d, want 0", got.TotalFailures)
+	}
+}
+
+func TestBreakerRecord(t *testing.T) {
+	b := New(tripAfter(3))
+	for i := 0; i < 3; i++ {
+		b.Record(errFail)
+	}
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after 3 recorded failures = %v, want open", got)
+	}
+	// Outcomes recorded while open are discarded.
+	b.Record(nil)
+	if got := b.Counts(); got.Requests != 0 {
+		t.Fatalf("Requests after open-state Record = %d, want 0", got.Requests)
+	}
+	// A recorded success counts as probe evidence once half-open.
+	time.Sleep(30 * time.Millisecond)
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state = %v, want half-open", got)
+	}
+	b.Record(nil)
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state after recorded probe success = %v, want closed", got)
+	}
+}
+
+func TestBreakerOnStateChange(t *testing.T) {
+	var transitions []string
+	cfg := tripAfter(1)
+	cfg.OnStateChange = func(name string, from, to State) {
+		transitions = append(transitions, from.String()+">"+to.String())
+	}
+	b := New(cfg)
+	fail(b)
+	time.Sleep(30 * time.Millisecond)
+	succeed(b)
+	want := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestBreakerOpensAndHistory(t *testing.T) {
+	b := New(tripAfter(1))
+	fail(b)
+	time.Sleep(30 * time.Millisecond)
+	fail(b)
+	if got := b.Opens(); got != 2 {
+		t.Fatalf("Opens = %d, want 2", got)
+	}
+	status := b.Status()
+	if len(status.RecentTransitions) == 0 {
+		t.Fatal("RecentTransitions is empty")
+	}
+	last := status.RecentTransitions[len(status.RecentTransitions)-1]
+	if last.To != StateOpen {
+		t.Fatalf("last transition to %v, want open", last.To)
+	}
+}
+
+func TestBreakerFailureWeight(t *testing.T) {
+	cfg := tripAfter(4)
+	cfg.FailureWeight = func(err error) uint64 { return 2 }
+	b := New(cfg)
+	fail(b)
+	fail(b)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after 2 double-weight failures = %v, want open", got)
+	}
+}
@@ -0,0 +1,151 @@
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OverflowName is the breaker name reported for breakers beyond the
+// cardinality limit of LimitCardinality.
+const OverflowName = "other"
+
+// CardinalityOption configures LimitCardinality.
+type CardinalityOption func(*cardinalityLimiter)
+
+// WithAllowedNames exempts the given breaker names from the cardinality
+// limit: they are always reported under their own name and do not count
+// against the limit. Use it to keep first-class dependencies visible while
+// per-host or per-key breakers aggregate into OverflowName.
+func WithAllowedNames(names ...string) CardinalityOption {
+	return func(l *cardinalityLimiter) {
+		for _, name := range names {
+			l.allowed[name] = struct{}{}
+		}
+	}
+}
+
+// LimitCardinality wraps collector so that at most limit distinct breaker
+// names reach it; events for further names are aggregated under
+// OverflowName. Breakers created per host or per key can otherwise grow
+// the name label without bound and blow up the metrics backend. Names are
+// admitted first come, first served.
+func LimitCardinality(collector MetricsCollector, limit int, opts ...CardinalityOption) MetricsCollector {
+	l := &cardinalityLimiter{
+		collector: collector,
+		limit:     limit,
+		seen:      make(map[string]struct{}),
+		allowed:   make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+type cardinalityLimiter struct {
+	collector MetricsCollector
+	limit     int
+
+	allowed map[string]struct{} // names exempt from the limit
+
+	mu   sync.Mutex
+	seen map[string]struct{} // names admitted under the limit
+}
+
+// rename maps a breaker name to the name reported downstream: itself while
+// under the limit or allowlisted, OverflowName beyond it.
+func (l *cardinalityLimiter) rename(name string) string {
+	if _, ok := l.allowed[name]; ok {
+		return name
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[name]; ok {
+		return name
+	}
+	if len(l.seen) >= l.limit {
+		return OverflowName
+	}
+	l.seen[name] = struct{}{}
+	return name
+}
+
+func (l *cardinalityLimiter) RecordSuccess(name string, duration time.Duration) {
+	l.collector.RecordSuccess(l.rename(name), duration)
+}
+
+func (l *cardinalityLimiter) RecordFailure(name string, duration time.Duration) {
+	l.collector.RecordFailure(l.rename(name), duration)
+}
+
+func (l *cardinalityLimiter) RecordRejection(name string) {
+	l.collector.RecordRejection(l.rename(name))
+}
+
+func (l *cardinalityLimiter) RecordStateChange(name string, from, to State) {
+	l.collector.RecordStateChange(l.rename(name), from, to)
+}
+
+// RecordProbeAttempt forwards to the wrapped collector if it implements
+// ProbeRecorder.
+func (l *cardinalityLimiter) RecordProbeAttempt(name string) {
+	if pr, ok := l.collector.(ProbeRecorder); ok {
+		pr.RecordProbeAttempt(l.rename(name))
+	}
+}
+
+// RecordProbeResult forwards to the wrapped collector if it implements
+// ProbeRecorder.
+func (l *cardinalityLimiter) RecordProbeResult(name string, success bool) {
+	if pr, ok := l.collector.(ProbeRecorder); ok {
+		pr.RecordProbeResult(l.rename(name), success)
+	}
+}
+
+// RecordRejectionReason forwards the reason if the wrapped collector
+// understands it and falls back to RecordRejection otherwise.
+func (l *cardinalityLimiter) RecordRejectionReason(name, reason string) {
+	recordRejection(l.collector, l.rename(name), reason)
+}
+
+// RecordFailureCategory forwards to the wrapped collector if it implements
+// FailureCategoryRecorder.
+func (l *cardinalityLimiter) RecordFailureCategory(name, category string) {
+	if fc, ok := l.collector.(FailureCategoryRecorder); ok {
+		fc.RecordFailureCategory(l.rename(name), category)
+	}
+}
+
+// RecordSuccessContext forwards the context if the wrapped collector
+// understands it and falls back to RecordSuccess otherwise.
+func (l *cardinalityLimiter) RecordSuccessContext(ctx context.Context, name string, duration time.Duration) {
+	if cr, ok := l.collector.(ContextRecorder); ok {
+		cr.RecordSuccessContext(ctx, l.rename(name), duration)
+	} else {
+		l.collector.RecordSuccess(l.rename(name), duration)
+	}
+}
+
+// RecordFailureContext forwards the context if the wrapped collector
+// understands it and falls back to RecordFailure otherwise.
+func (l *cardinalityLimiter) RecordFailureContext(ctx context.Context, name string, duration time.Duration) {
+	if cr, ok := l.collector.(ContextRecorder); ok {
+		cr.RecordFailureContext(ctx, l.rename(name), duration)
+	} else {
+		l.collector.RecordFailure(l.rename(name), duration)
+	}
+}
+
+// RecordAttributes forwards to the wrapped collector if it implements
+// AttributeRecorder. Attributes for overflowed breakers are dropped: they
+// would otherwise overwrite each other under OverflowName.
+func (l *cardinalityLimiter) RecordAttributes(name string, attrs map[string]string) {
+	renamed := l.rename(name)
+	if renamed == OverflowName {
+		return
+	}
+	if ar, ok := l.collector.(AttributeRecorder); ok {
+		ar.RecordAttributes(renamed, attrs)
+	}
+}
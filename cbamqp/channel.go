@@ -0,0 +1,190 @@
+// Package cbamqp protects RabbitMQ publishing with a circuit breaker.
+// The wrapped channel runs in confirm mode and the broker's publisher
+// confirm — not the write to the socket — is the success signal, so a
+// broker that accepts bytes but can't route or persist them still trips
+// the breaker. While the circuit is open, publishes can optionally be
+// buffered in a bounded in-memory queue and replayed after recovery.
+package cbamqp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ErrNacked is returned — and recorded as the failure — when the broker
+// negatively acknowledges a publish.
+var ErrNacked = errors.New("cbamqp: broker nacked publish")
+
+// ErrConfirmTimeout is returned — and recorded as the failure — when no
+// confirm arrives within the confirm timeout.
+var ErrConfirmTimeout = errors.New("cbamqp: timed out waiting for publisher confirm")
+
+// ErrQueueFull is returned when the circuit is open and the replay queue
+// configured by WithQueueWhileOpen has no room left.
+var ErrQueueFull = errors.New("cbamqp: open-state publish queue is full")
+
+// defaultConfirmTimeout bounds how long a publish waits for its confirm.
+const defaultConfirmTimeout = 5 * time.Second
+
+// Channel wraps an *amqp.Channel in confirm mode with breaker admission.
+// Construct it with WrapChannel; the zero value is not usable.
+type Channel struct {
+	ch             *amqp.Channel
+	breaker        *circuitbreaker.Breaker
+	confirmTimeout time.Duration
+
+	mu        sync.Mutex
+	queue     []publish // bounded by queueCap; head is the oldest shed publish
+	queueCap  int
+	replaying bool
+}
+
+// publish holds the arguments of a shed publish awaiting replay.
+type publish struct {
+	exchange, key        string
+	mandatory, immediate bool
+	msg                  amqp.Publishing
+}
+
+// Option configures the wrapper.
+type Option func(*Channel)
+
+// WithConfirmTimeout bounds the wait for the broker's confirm; waiting
+// longer counts as a failure. The default is 5s.
+func WithConfirmTimeout(d time.Duration) Option {
+	return func(c *Channel) { c.confirmTimeout = d }
+}
+
+// WithQueueWhileOpen buffers up to n publishes shed by an open circuit
+// and replays them, oldest first, once publishes are being confirmed
+// again; PublishWithContext returns ErrQueueFull when the buffer is
+// exhausted. The queue is in-memory only — publishes still buffered when
+// the process exits are lost, so size it for blips, not outages.
+func WithQueueWhileOpen(n int) Option {
+	return func(c *Channel) { c.queueCap = n }
+}
+
+// WrapChannel puts ch into confirm mode and returns it wrapped with
+// breaker admission.
+func WrapChannel(ch *amqp.Channel, breaker *circuitbreaker.Breaker, opts ...Option) (*Channel, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, err
+	}
+	c := &Channel{ch: ch, breaker: breaker, confirmTimeout: defaultConfirmTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Channel returns the underlying channel, for declarations and consuming.
+func (c *Channel) Channel() *amqp.Channel { return c.ch }
+
+// PublishWithContext publishes msg through the breaker and waits for the
+// broker's confirm. A nack, a confirm timeout, and a failed write all
+// count as failures; caller cancellation is recorded neutrally.
+func (c *Channel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	done, err := c.breaker.AllowContext(ctx)
+	if err != nil {
+		return c.shed(publish{exchange, key, mandatory, immediate, msg}, err)
+	}
+	pubErr := c.confirmed(ctx, publish{exchange, key, mandatory, immediate, msg})
+	if errors.Is(pubErr, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+		done(nil)
+	} else {
+		done(pubErr)
+	}
+	if pubErr == nil {
+		c.replay()
+	}
+	return pubErr
+}
+
+// confirmed sends one publish and resolves its confirm.
+func (c *Channel) confirmed(ctx context.Context, p publish) error {
+	conf, err := c.ch.PublishWithDeferredConfirmWithContext(ctx, p.exchange, p.key, p.mandatory, p.immediate, p.msg)
+	if err != nil {
+		return err
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, c.confirmTimeout)
+	defer cancel()
+	acked, err := conf.WaitContext(waitCtx)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		return ErrConfirmTimeout
+	case err != nil:
+		return err
+	case !acked:
+		return ErrNacked
+	}
+	return nil
+}
+
+// Queued reports how many shed publishes are waiting for replay.
+func (c *Channel) Queued() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queue)
+}
+
+func (c *Channel) shed(p publish, err error) error {
+	if c.queueCap == 0 {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.queue) >= c.queueCap {
+		return ErrQueueFull
+	}
+	c.queue = append(c.queue, p)
+	return nil
+}
+
+// replay flushes the queue, oldest first, after a confirmed publish,
+// stopping at the first rejection or failure. Each publish is dequeued
+// only once it confirms, so one that doesn't stays at the head and order
+// survives open/close flapping.
+func (c *Channel) replay() {
+	if c.queueCap == 0 {
+		return
+	}
+	c.mu.Lock()
+	if c.replaying { // a concurrent publish is already draining
+		c.mu.Unlock()
+		return
+	}
+	c.replaying = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.replaying = false
+		c.mu.Unlock()
+	}()
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		p := c.queue[0]
+		c.mu.Unlock()
+		done, err := c.breaker.Allow()
+		if err != nil {
+			return
+		}
+		pubErr := c.confirmed(context.Background(), p)
+		done(pubErr)
+		if pubErr != nil {
+			return
+		}
+		c.mu.Lock()
+		c.queue = c.queue[1:]
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,65 @@
+package cbamqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// The shed and queue paths run entirely before the wrapped *amqp.Channel
+// is touched, so they are tested here without a broker; confirm handling
+// and replay are exercised by integration environments with a real
+// RabbitMQ.
+
+func openChannel(queueCap int) *Channel {
+	b := circuitbreaker.New(circuitbreaker.Config{OpenTimeout: time.Minute})
+	b.ForceOpen()
+	return &Channel{breaker: b, confirmTimeout: defaultConfirmTimeout, queueCap: queueCap}
+}
+
+func TestPublishWhileOpenReturnsBreakerError(t *testing.T) {
+	c := openChannel(0)
+	err := c.PublishWithContext(context.Background(), "events", "orders.created", false, false, amqp.Publishing{})
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("publish = %v, want ErrOpen", err)
+	}
+}
+
+func TestPublishWhileOpenQueuesUntilFull(t *testing.T) {
+	c := openChannel(2)
+	for i, key := range []string{"first", "second"} {
+		err := c.PublishWithContext(context.Background(), "events", key, false, false, amqp.Publishing{})
+		if err != nil {
+			t.Fatalf("publish %d = %v, want nil (queued)", i, err)
+		}
+	}
+	if got := c.Queued(); got != 2 {
+		t.Fatalf("Queued = %d, want 2", got)
+	}
+	err := c.PublishWithContext(context.Background(), "events", "third", false, false, amqp.Publishing{})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("publish past capacity = %v, want ErrQueueFull", err)
+	}
+
+	// Replay is oldest-first; the queue must hold the publishes in
+	// arrival order with the oldest at the head.
+	if c.queue[0].key != "first" || c.queue[1].key != "second" {
+		t.Fatalf("queue keys = [%s %s], want [first second]", c.queue[0].key, c.queue[1].key)
+	}
+}
+
+func TestReplayKeepsHeadWhileRejected(t *testing.T) {
+	// With the breaker still open, replay must not consume the queue:
+	// the head publish stays queued until it actually confirms.
+	c := openChannel(2)
+	c.PublishWithContext(context.Background(), "events", "first", false, false, amqp.Publishing{})
+	c.replay()
+	if got := c.Queued(); got != 1 {
+		t.Fatalf("Queued after rejected replay = %d, want 1", got)
+	}
+}
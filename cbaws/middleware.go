@@ -0,0 +1,164 @@
+// Package cbaws wraps aws-sdk-go-v2 API calls in circuit breakers via a
+// Finalize-step middleware, keyed per service so a melting DynamoDB
+// doesn't shed S3 traffic. Throttling and server faults count against
+// the breaker; client faults (validation, access denied) do not. Append
+// the middleware to the config's APIOptions:
+//
+//	cfg.APIOptions = append(cfg.APIOptions, cbaws.WithBreakers(registry))
+package cbaws
+
+import (
+	"context"
+	"errors"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	smithy "github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// OpenError is what API calls return when the circuit sheds them. It
+// wraps circuitbreaker.ErrOpen (or ErrTooManyRequests) and reports
+// itself non-retryable so the SDK's retryer fails fast instead of
+// burning its attempt budget against a circuit that won't close within
+// it; the condition is transient, so callers that match ErrOpen with
+// errors.Is can retry after their own backoff.
+type OpenError struct {
+	Err error
+}
+
+func (e *OpenError) Error() string { return "cbaws: " + e.Err.Error() }
+
+func (e *OpenError) Unwrap() error { return e.Err }
+
+// RetryableError implements the smithy retryer's classification
+// interface; always false — see the type comment.
+func (e *OpenError) RetryableError() bool { return false }
+
+// throttleCodes are the API error codes AWS services use to signal
+// throttling, across service dialects.
+var throttleCodes = map[string]struct{}{
+	"Throttling":                             {},
+	"ThrottlingException":                    {},
+	"ThrottledException":                     {},
+	"RequestThrottled":                       {},
+	"RequestThrottledException":              {},
+	"TooManyRequestsException":               {},
+	"RequestLimitExceeded":                   {},
+	"ProvisionedThroughputExceededException": {},
+	"SlowDown":                               {},
+}
+
+// Middleware is the Finalize-step middleware; construct it with New and
+// install it with WithBreakers or Register.
+type Middleware struct {
+	breaker        *circuitbreaker.Breaker
+	registry       *circuitbreaker.Registry
+	countThrottles bool
+}
+
+// Option configures the middleware.
+type Option func(*Middleware)
+
+// WithIgnoreThrottling records throttled calls neutrally instead of as
+// failures. The default counts them: a service telling you to back off
+// is exactly the load an open circuit sheds. Ignore them when the
+// breaker should only react to the service being down, not busy.
+func WithIgnoreThrottling() Option {
+	return func(m *Middleware) { m.countThrottles = false }
+}
+
+// New returns a Middleware with one breaker per AWS service, created on
+// demand from registry.
+func New(registry *circuitbreaker.Registry, opts ...Option) *Middleware {
+	m := &Middleware{registry: registry, countThrottles: true}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewWithBreaker is New with a single breaker for every service — for
+// clients that only talk to one.
+func NewWithBreaker(breaker *circuitbreaker.Breaker, opts ...Option) *Middleware {
+	m := &Middleware{breaker: breaker, countThrottles: true}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// WithBreakers returns the APIOptions function installing New(registry,
+// opts...) into each operation's middleware stack.
+func WithBreakers(registry *circuitbreaker.Registry, opts ...Option) func(*middleware.Stack) error {
+	return New(registry, opts...).Register
+}
+
+// Register adds the middleware to stack, after the retryer so admission
+// and recording happen per attempt — an open circuit then fails the
+// first attempt with a non-retryable OpenError and the call returns
+// immediately.
+func (m *Middleware) Register(stack *middleware.Stack) error {
+	return stack.Finalize.Add(m, middleware.After)
+}
+
+// ID implements middleware.FinalizeMiddleware.
+func (m *Middleware) ID() string { return "CircuitBreaker" }
+
+// HandleFinalize implements middleware.FinalizeMiddleware.
+func (m *Middleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	b := m.breakerFor(ctx)
+	done, err := b.AllowContext(ctx)
+	if err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, &OpenError{Err: err}
+	}
+	out, md, err := next.HandleFinalize(ctx, in)
+	done(m.classify(ctx, err))
+	return out, md, err
+}
+
+func (m *Middleware) breakerFor(ctx context.Context) *circuitbreaker.Breaker {
+	if m.registry != nil {
+		return m.registry.Get(awsmiddleware.GetServiceID(ctx))
+	}
+	return m.breaker
+}
+
+// classify maps an attempt's error to the breaker outcome: server
+// faults, transport errors and (by default) throttling count; client
+// faults and caller cancellation do not.
+func (m *Middleware) classify(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	var ae smithy.APIError
+	if errors.As(err, &ae) {
+		if _, throttled := throttleCodes[ae.ErrorCode()]; throttled {
+			if m.countThrottles {
+				return err
+			}
+			return nil
+		}
+		if ae.ErrorFault() == smithy.FaultClient {
+			return nil
+		}
+		if ae.ErrorFault() == smithy.FaultServer {
+			return err
+		}
+	}
+	// Fault unknown: fall back to the HTTP status when there is one.
+	var re *smithyhttp.ResponseError
+	if errors.As(err, &re) {
+		if code := re.HTTPStatusCode(); code == 429 || code >= 500 {
+			return err
+		}
+		return nil
+	}
+	// No response at all — connection refused, DNS, timeout.
+	return err
+}
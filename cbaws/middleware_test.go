@@ -0,0 +1,104 @@
+package cbaws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	smithy "github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+// attempt runs one call through the middleware with a next handler
+// replying err, counting how often next is reached.
+func attempt(m *Middleware, err error, calls *int) error {
+	next := middleware.FinalizeHandlerFunc(func(context.Context, middleware.FinalizeInput) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		*calls++
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+	})
+	_, _, outErr := m.HandleFinalize(context.Background(), middleware.FinalizeInput{}, next)
+	return outErr
+}
+
+func serverFault(code string) error {
+	return &smithy.GenericAPIError{Code: code, Message: "server error", Fault: smithy.FaultServer}
+}
+
+func TestMiddlewareTripsAndFailsFastNonRetryable(t *testing.T) {
+	breaker := tripAfter(2)
+	m := NewWithBreaker(breaker)
+	calls := 0
+
+	for i := 0; i < 2; i++ {
+		attempt(m, serverFault("InternalFailure"), &calls)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	err := attempt(m, nil, &calls)
+	var oe *OpenError
+	if !errors.As(err, &oe) || !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("shed attempt = %v, want *OpenError wrapping ErrOpen", err)
+	}
+	if oe.RetryableError() {
+		t.Fatal("OpenError is retryable; the SDK would burn its attempt budget on an open circuit")
+	}
+	if calls != 2 {
+		t.Fatalf("next ran %d times, want 2 (open circuit must not reach it)", calls)
+	}
+}
+
+func TestMiddlewareClientFaultIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	m := NewWithBreaker(breaker)
+	calls := 0
+
+	attempt(m, &smithy.GenericAPIError{Code: "AccessDenied", Fault: smithy.FaultClient}, &calls)
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (client faults are the caller's problem)", got)
+	}
+	if got := breaker.Counts(); got.TotalSuccesses != 1 {
+		t.Fatalf("TotalSuccesses = %d, want 1", got.TotalSuccesses)
+	}
+}
+
+func TestMiddlewareThrottlingCounts(t *testing.T) {
+	throttle := &smithy.GenericAPIError{Code: "ThrottlingException", Fault: smithy.FaultClient}
+	calls := 0
+
+	breaker := tripAfter(1)
+	attempt(NewWithBreaker(breaker), throttle, &calls)
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (throttling counts by default)", got)
+	}
+
+	ignoring := tripAfter(1)
+	attempt(NewWithBreaker(ignoring, WithIgnoreThrottling()), throttle, &calls)
+	if got := ignoring.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed under WithIgnoreThrottling", got)
+	}
+}
+
+func TestMiddlewareCanceledAttemptIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	m := NewWithBreaker(breaker)
+	calls := 0
+
+	attempt(m, context.Canceled, &calls)
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (cancellation is neutral)", got)
+	}
+}
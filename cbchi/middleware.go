@@ -0,0 +1,41 @@
+// Package cbchi adapts the circuit breaker middleware to chi. Chi
+// middleware is plain net/http, so the package is thin: a route-key
+// extractor reading chi's RouteContext, and a constructor wiring it into
+// cbhttp.HTTPMiddleware — breakers and metrics are then keyed by chi's
+// route pattern ("/v1/users/{id}") instead of the raw path, so path
+// parameters don't explode cardinality.
+package cbchi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbhttp"
+)
+
+// RouteKey returns the request's breaker key as method plus chi route
+// pattern, e.g. "GET /v1/users/{id}", and "unmatched" when chi has not
+// matched a route. Chi resolves the pattern during routing, so the key is
+// only meaningful for middleware attached inside the router — with
+// Router.With or a route group — not for middleware wrapped around the
+// router itself.
+func RouteKey(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return r.Method + " " + pattern
+		}
+	}
+	return r.Method + " unmatched"
+}
+
+// Middleware returns a chi-compatible middleware with a breaker per route
+// pattern, fetched from registry and created on demand with its defaults.
+// opts configure the underlying cbhttp.HTTPMiddleware (fallbacks,
+// bulkhead, observer, ...); a cbhttp.WithRouteBreakers among them
+// overrides the route keying.
+func Middleware(registry *circuitbreaker.Registry, opts ...cbhttp.MiddlewareOption) func(http.Handler) http.Handler {
+	opts = append([]cbhttp.MiddlewareOption{cbhttp.WithRouteBreakers(registry, RouteKey)}, opts...)
+	return cbhttp.NewMiddleware(nil, opts...).Wrap
+}
@@ -0,0 +1,63 @@
+package cbchi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func newRegistry() *circuitbreaker.Registry {
+	return circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestMiddlewareKeysByRoutePattern(t *testing.T) {
+	registry := newRegistry()
+	// Attach with Router.With so chi has matched before the middleware
+	// runs — under router.Use it runs pre-matching and every request
+	// would key as "GET unmatched" (see RouteKey).
+	mw := Middleware(registry)
+	router := chi.NewRouter()
+	router.With(mw).Get("/bad/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	router.With(mw).Get("/good", func(w http.ResponseWriter, r *http.Request) {})
+
+	get(t, router, "/bad/1")
+
+	// The breaker keys on the route pattern, so a different id shares the
+	// open breaker and the key set stays bounded.
+	if _, ok := registry.Lookup("GET /bad/{id}"); !ok {
+		t.Fatal("no breaker registered under the route pattern")
+	}
+	if rec := get(t, router, "/bad/2"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/bad/2 status = %d, want 503", rec.Code)
+	}
+	if rec := get(t, router, "/good"); rec.Code != http.StatusOK {
+		t.Fatalf("/good status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRouteKeyOutsideRouter(t *testing.T) {
+	// Wrapped around the router — not inside it — chi has not matched
+	// yet, so requests fall into the shared "unmatched" key.
+	if got := RouteKey(httptest.NewRequest(http.MethodGet, "/anything", nil)); got != "GET unmatched" {
+		t.Fatalf("RouteKey = %q, want \"GET unmatched\"", got)
+	}
+}
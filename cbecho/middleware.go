@@ -0,0 +1,117 @@
+// Package cbecho adapts the circuit breaker middleware to echo. Instead
+// of writing its own 503, the adapter returns an *echo.HTTPError for shed
+// requests so echo's error handler renders the rejection consistently
+// with the rest of the application, and per-route breakers are keyed by
+// echo's route template.
+package cbecho
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbhttp"
+)
+
+// middleware carries the adapter's configuration.
+type middleware struct {
+	breaker      *circuitbreaker.Breaker
+	registry     *circuitbreaker.Registry
+	isSuccessful func(status int) bool
+	observer     cbhttp.MiddlewareObserver
+}
+
+// Option configures the middleware.
+type Option func(*middleware)
+
+// WithRouteBreakers keys the middleware by echo's route template
+// (c.Path(), e.g. "/v1/users/:id") plus method instead of one breaker for
+// the whole server. Breakers come from registry, created on demand with
+// its defaults.
+func WithRouteBreakers(registry *circuitbreaker.Registry) Option {
+	return func(m *middleware) { m.registry = registry }
+}
+
+// WithSuccessfulStatus sets the predicate classifying a handler's
+// response status; the default treats anything below 500 as success.
+func WithSuccessfulStatus(fn func(status int) bool) Option {
+	return func(m *middleware) { m.isSuccessful = fn }
+}
+
+// WithObserver registers obs — e.g. prom.NewServerMetrics — for every
+// request the middleware sees, with echo's route template as the path.
+func WithObserver(obs cbhttp.MiddlewareObserver) Option {
+	return func(m *middleware) { m.observer = obs }
+}
+
+// Middleware returns an echo.MiddlewareFunc guarding handlers with
+// breaker. The breaker may be nil if WithRouteBreakers is used. Shed
+// requests surface as *echo.HTTPError with status 503 and a Retry-After
+// header derived from the breaker's time until half-open.
+func Middleware(breaker *circuitbreaker.Breaker, opts ...Option) echo.MiddlewareFunc {
+	m := &middleware{
+		breaker:      breaker,
+		isSuccessful: func(status int) bool { return status < 500 },
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			b := m.breakerFor(c)
+			done, err := b.AllowContext(c.Request().Context())
+			if err != nil {
+				if m.observer != nil {
+					m.observer.ObserveShed(c.Request().Method, c.Path(), cbhttp.ShedReason(err))
+				}
+				c.Response().Header().Set("Retry-After", cbhttp.RetryAfterValue(b))
+				return echo.NewHTTPError(http.StatusServiceUnavailable,
+					"service temporarily unavailable").SetInternal(err)
+			}
+			start := time.Now()
+			handlerErr := next(c)
+			status := responseStatus(c, handlerErr)
+			if m.observer != nil {
+				m.observer.ObserveHandled(c.Request().Method, c.Path(), status, time.Since(start))
+			}
+			switch {
+			case c.Request().Context().Err() != nil:
+				// Client went away mid-request; don't let aborts trip
+				// the breaker (see cbhttp.HTTPMiddleware).
+				done(nil)
+			case m.isSuccessful(status):
+				done(nil)
+			case handlerErr != nil:
+				done(handlerErr)
+			default:
+				done(&cbhttp.StatusError{StatusCode: status, Status: http.StatusText(status)})
+			}
+			return handlerErr
+		}
+	}
+}
+
+func (m *middleware) breakerFor(c echo.Context) *circuitbreaker.Breaker {
+	if m.registry != nil {
+		return m.registry.Get(c.Request().Method + " " + c.Path())
+	}
+	return m.breaker
+}
+
+// responseStatus resolves the status the request will be answered with:
+// the written status, or for an unhandled handler error the status echo's
+// error handler will write (the *echo.HTTPError code, 500 otherwise).
+func responseStatus(c echo.Context, handlerErr error) int {
+	if c.Response().Committed {
+		return c.Response().Status
+	}
+	if handlerErr == nil {
+		return c.Response().Status
+	}
+	if he, ok := handlerErr.(*echo.HTTPError); ok {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
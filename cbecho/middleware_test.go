@@ -0,0 +1,106 @@
+package cbecho
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+func TestMiddlewareTripsAndSheds(t *testing.T) {
+	breaker := tripAfter(1)
+	handled := 0
+
+	e := echo.New()
+	e.Use(Middleware(breaker))
+	e.GET("/work", func(c echo.Context) error {
+		handled++
+		return echo.NewHTTPError(http.StatusBadGateway, "upstream down")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/work", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", rec.Code)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	// Shed requests go through echo's error handler as 503s.
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/work", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status while open = %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("shed response missing Retry-After")
+	}
+	if handled != 1 {
+		t.Fatalf("handler ran %d times, want 1", handled)
+	}
+}
+
+func TestMiddlewareShedErrorWrapsSentinel(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+
+	e := echo.New()
+	h := Middleware(breaker)(func(c echo.Context) error { return nil })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	err := h(c)
+	var he *echo.HTTPError
+	if !errors.As(err, &he) || he.Code != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want 503 *echo.HTTPError", err)
+	}
+	if !errors.Is(he.Internal, circuitbreaker.ErrOpen) {
+		t.Fatalf("internal = %v, want ErrOpen", he.Internal)
+	}
+}
+
+func TestMiddlewareRouteBreakers(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+
+	e := echo.New()
+	e.Use(Middleware(nil, WithRouteBreakers(registry)))
+	e.GET("/bad/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadGateway, "upstream down")
+	})
+	e.GET("/good", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bad/1", nil))
+
+	// Breakers key on the route template, so /bad/2 shares /bad/1's open
+	// breaker while /good is untouched.
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bad/2", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/bad/2 status = %d, want 503", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/good", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/good status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,130 @@
+// Package cbfiber adapts the circuit breaker middleware to fiber. Fiber
+// is built on fasthttp rather than net/http, so the cbhttp middleware
+// cannot wrap fiber handlers at all; this adapter speaks fiber.Handler
+// natively, keys per-route breakers by fiber's route template, and
+// rejects shed requests through fiber's error handler.
+package cbfiber
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbhttp"
+)
+
+// middleware carries the adapter's configuration.
+type middleware struct {
+	breaker      *circuitbreaker.Breaker
+	registry     *circuitbreaker.Registry
+	isSuccessful func(status int) bool
+	observer     cbhttp.MiddlewareObserver
+}
+
+// Option configures the middleware.
+type Option func(*middleware)
+
+// WithRouteBreakers keys the middleware by fiber's route template
+// (c.Route().Path, e.g. "/v1/users/:id") plus method instead of one
+// breaker for the whole app. Breakers come from registry, created on
+// demand with its defaults.
+//
+// Per-route keying requires attaching the middleware on the routes
+// themselves (app.Get("/v1/users/:id", mw, handler)): fiber resolves the
+// terminal route only as c.Next() advances matching, so for a handler
+// registered with app.Use, c.Route().Path is the middleware's own mount
+// path and every request would share one breaker.
+func WithRouteBreakers(registry *circuitbreaker.Registry) Option {
+	return func(m *middleware) { m.registry = registry }
+}
+
+// WithSuccessfulStatus sets the predicate classifying a handler's
+// response status; the default treats anything below 500 as success.
+func WithSuccessfulStatus(fn func(status int) bool) Option {
+	return func(m *middleware) { m.isSuccessful = fn }
+}
+
+// WithObserver registers obs — e.g. prom.NewServerMetrics — for every
+// request the middleware sees, with fiber's route template as the path.
+func WithObserver(obs cbhttp.MiddlewareObserver) Option {
+	return func(m *middleware) { m.observer = obs }
+}
+
+// Middleware returns a fiber.Handler guarding handlers with breaker. The
+// breaker may be nil if WithRouteBreakers is used. Shed requests surface
+// as *fiber.Error with status 503, rendered by the app's error handler,
+// with a Retry-After header derived from the breaker's time until
+// half-open.
+func Middleware(breaker *circuitbreaker.Breaker, opts ...Option) fiber.Handler {
+	m := &middleware{
+		breaker:      breaker,
+		isSuccessful: func(status int) bool { return status < 500 },
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return func(c *fiber.Ctx) error {
+		b := m.breakerFor(c)
+		done, err := b.AllowContext(c.Context())
+		if err != nil {
+			if m.observer != nil {
+				m.observer.ObserveShed(c.Method(), routePath(c), cbhttp.ShedReason(err))
+			}
+			c.Set(fiber.HeaderRetryAfter, cbhttp.RetryAfterValue(b))
+			return fiber.NewError(fiber.StatusServiceUnavailable, "service temporarily unavailable")
+		}
+		start := time.Now()
+		handlerErr := c.Next()
+		status := responseStatus(c, handlerErr)
+		if m.observer != nil {
+			m.observer.ObserveHandled(c.Method(), routePath(c), status, time.Since(start))
+		}
+		switch {
+		case c.Context().Err() != nil:
+			// Client went away mid-request; don't let aborts trip the
+			// breaker (see cbhttp.HTTPMiddleware).
+			done(nil)
+		case m.isSuccessful(status):
+			done(nil)
+		case handlerErr != nil:
+			done(handlerErr)
+		default:
+			done(&cbhttp.StatusError{StatusCode: status, Status: http.StatusText(status)})
+		}
+		return handlerErr
+	}
+}
+
+func (m *middleware) breakerFor(c *fiber.Ctx) *circuitbreaker.Breaker {
+	if m.registry != nil {
+		return m.registry.Get(c.Method() + " " + routePath(c))
+	}
+	return m.breaker
+}
+
+// routePath returns fiber's route template for the request, "unmatched"
+// when no route matched — raw paths would make the breaker and metrics
+// keys unbounded. The template is only the terminal route's when the
+// middleware is attached on the route itself; see WithRouteBreakers.
+func routePath(c *fiber.Ctx) string {
+	if route := c.Route(); route != nil && route.Path != "" {
+		return route.Path
+	}
+	return "unmatched"
+}
+
+// responseStatus resolves the status the request will be answered with:
+// the written status, or for an unhandled handler error the status
+// fiber's error handler will write (the *fiber.Error code, 500
+// otherwise).
+func responseStatus(c *fiber.Ctx, handlerErr error) int {
+	if handlerErr == nil {
+		return c.Response().StatusCode()
+	}
+	if fe, ok := handlerErr.(*fiber.Error); ok {
+		return fe.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,107 @@
+package cbfiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+func get(t *testing.T, app *fiber.App, path string) *http.Response {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("Test %s: %v", path, err)
+	}
+	return resp
+}
+
+func TestMiddlewareTripsAndSheds(t *testing.T) {
+	breaker := tripAfter(1)
+	handled := 0
+
+	app := fiber.New()
+	app.Use(Middleware(breaker))
+	app.Get("/work", func(c *fiber.Ctx) error {
+		handled++
+		return fiber.NewError(fiber.StatusBadGateway, "upstream down")
+	})
+
+	if resp := get(t, app, "/work"); resp.StatusCode != fiber.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", resp.StatusCode)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	resp := get(t, app, "/work")
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Fatalf("status while open = %d, want 503", resp.StatusCode)
+	}
+	if resp.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Fatal("shed response missing Retry-After")
+	}
+	if handled != 1 {
+		t.Fatalf("handler ran %d times, want 1", handled)
+	}
+}
+
+func TestMiddlewareSuccessIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+
+	app := fiber.New()
+	app.Use(Middleware(breaker))
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNotFound)
+	})
+
+	get(t, app, "/missing")
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (a 404 is the client's problem)", got)
+	}
+	if got := breaker.Counts(); got.TotalSuccesses != 1 {
+		t.Fatalf("TotalSuccesses = %d, want 1", got.TotalSuccesses)
+	}
+}
+
+func TestMiddlewareRouteBreakers(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+
+	// Per-route keying needs the middleware on the routes themselves —
+	// under app.Use, c.Route().Path is the mount path, not the template.
+	mw := Middleware(nil, WithRouteBreakers(registry))
+	app := fiber.New()
+	app.Get("/bad/:id", mw, func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusBadGateway, "upstream down")
+	})
+	app.Get("/good", mw, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	get(t, app, "/bad/1")
+
+	// Breakers key on the route template, so /bad/2 shares /bad/1's open
+	// breaker while /good is untouched.
+	if resp := get(t, app, "/bad/2"); resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Fatalf("/bad/2 status = %d, want 503", resp.StatusCode)
+	}
+	if resp := get(t, app, "/good"); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("/good status = %d, want 200", resp.StatusCode)
+	}
+}
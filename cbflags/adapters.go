@@ -0,0 +1,76 @@
+package cbflags
+
+import "context"
+
+// LaunchDarklyClient is the subset of the LaunchDarkly Go SDK client used
+// by the adapter. *ldclient.LDClient satisfies it through a small wrapper
+// that binds the evaluation context.
+type LaunchDarklyClient interface {
+	BoolVariation(key string, defaultVal bool) (bool, error)
+	IntVariation(key string, defaultVal int) (int, error)
+}
+
+// LaunchDarklyProvider adapts a LaunchDarkly client to Provider.
+type LaunchDarklyProvider struct {
+	client LaunchDarklyClient
+}
+
+// NewLaunchDarklyProvider returns a Provider backed by client.
+func NewLaunchDarklyProvider(client LaunchDarklyClient) *LaunchDarklyProvider {
+	return &LaunchDarklyProvider{client: client}
+}
+
+// Bool implements Provider.
+func (p *LaunchDarklyProvider) Bool(_ context.Context, key string, def bool) bool {
+	v, err := p.client.BoolVariation(key, def)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Int implements Provider.
+func (p *LaunchDarklyProvider) Int(_ context.Context, key string, def int) int {
+	v, err := p.client.IntVariation(key, def)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// OpenFeatureClient is the subset of the OpenFeature client used by the
+// adapter; *openfeature.Client satisfies it.
+type OpenFeatureClient interface {
+	BooleanValue(ctx context.Context, flag string, defaultValue bool, evalCtx any) (bool, error)
+	IntValue(ctx context.Context, flag string, defaultValue int64, evalCtx any) (int64, error)
+}
+
+// OpenFeatureProvider adapts an OpenFeature client to Provider.
+type OpenFeatureProvider struct {
+	client  OpenFeatureClient
+	evalCtx any
+}
+
+// NewOpenFeatureProvider returns a Provider backed by client. evalCtx is
+// passed through to every evaluation and may be nil.
+func NewOpenFeatureProvider(client OpenFeatureClient, evalCtx any) *OpenFeatureProvider {
+	return &OpenFeatureProvider{client: client, evalCtx: evalCtx}
+}
+
+// Bool implements Provider.
+func (p *OpenFeatureProvider) Bool(ctx context.Context, key string, def bool) bool {
+	v, err := p.client.BooleanValue(ctx, key, def, p.evalCtx)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Int implements Provider.
+func (p *OpenFeatureProvider) Int(ctx context.Context, key string, def int) int {
+	v, err := p.client.IntValue(ctx, key, int64(def), p.evalCtx)
+	if err != nil {
+		return def
+	}
+	return int(v)
+}
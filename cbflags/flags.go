@@ -0,0 +1,164 @@
+// Package cbflags drives circuit breakers from a feature-flag provider, so
+// rollout tooling that already lives behind flags can force breakers open
+// or closed and tune their thresholds without a deploy.
+//
+// As with package cbredis, the package depends on a small Provider
+// interface rather than a concrete SDK; adapters for LaunchDarkly- and
+// OpenFeature-shaped clients are included.
+package cbflags
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Provider evaluates feature flags. Implementations must return the given
+// default when a flag is missing or the provider is unavailable.
+type Provider interface {
+	Bool(ctx context.Context, key string, def bool) bool
+	Int(ctx context.Context, key string, def int) int
+}
+
+// Controller applies flag values to the breakers of a registry. For a
+// breaker named "payments" and the default key prefix it evaluates:
+//
+//	cb.payments.force-open   — pin the breaker open while true
+//	cb.payments.force-close  — pin the breaker closed while true
+//	cb.payments.failure-threshold — consecutive-failure trip threshold
+//
+// force-open wins if both force flags are set.
+type Controller struct {
+	provider  Provider
+	registry  *circuitbreaker.Registry
+	keyPrefix string
+	interval  time.Duration
+
+	mu      sync.Mutex
+	applied map[string]forceState
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type forceState int
+
+const (
+	forceNone forceState = iota
+	forceOpen
+	forceClose
+)
+
+// Option configures a Controller.
+type Option func(*Controller)
+
+// WithKeyPrefix sets the flag key prefix. The default is "cb.".
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Controller) { c.keyPrefix = prefix }
+}
+
+// WithPollInterval sets how often flags are re-evaluated. The default is
+// 10 seconds.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Controller) { c.interval = interval }
+}
+
+// NewController returns a Controller driving registry's breakers from
+// provider. Call Start to begin polling.
+func NewController(provider Provider, registry *circuitbreaker.Registry, opts ...Option) *Controller {
+	c := &Controller{
+		provider:  provider,
+		registry:  registry,
+		keyPrefix: "cb.",
+		interval:  10 * time.Second,
+		applied:   make(map[string]forceState),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ReadyToTrip returns a trip predicate that re-reads the breaker's
+// failure-threshold flag on every failure, so threshold changes take
+// effect without recreating the breaker. Use it as Config.ReadyToTrip.
+func (c *Controller) ReadyToTrip(name string, defaultThreshold int) func(circuitbreaker.Counts) bool {
+	key := c.keyPrefix + name + ".failure-threshold"
+	return func(counts circuitbreaker.Counts) bool {
+		threshold := c.provider.Int(context.Background(), key, defaultThreshold)
+		return counts.ConsecutiveFailures >= uint64(threshold)
+	}
+}
+
+// Start begins polling flags in a background goroutine.
+func (c *Controller) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		return
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run(c.stop, c.done)
+}
+
+// Stop ends the polling loop.
+func (c *Controller) Stop() {
+	c.mu.Lock()
+	if c.stop == nil {
+		c.mu.Unlock()
+		return
+	}
+	close(c.stop)
+	done := c.done
+	c.stop, c.done = nil, nil
+	c.mu.Unlock()
+	<-done
+}
+
+func (c *Controller) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.Apply(context.Background())
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Apply evaluates the force flags once and applies any changes. It is
+// called periodically by Start but may also be invoked directly, e.g. from
+// a flag-change webhook.
+func (c *Controller) Apply(ctx context.Context) {
+	for _, b := range c.registry.All() {
+		name := b.Name()
+		want := forceNone
+		switch {
+		case c.provider.Bool(ctx, c.keyPrefix+name+".force-open", false):
+			want = forceOpen
+		case c.provider.Bool(ctx, c.keyPrefix+name+".force-close", false):
+			want = forceClose
+		}
+
+		c.mu.Lock()
+		previous := c.applied[name]
+		c.applied[name] = want
+		c.mu.Unlock()
+		if want == previous {
+			continue
+		}
+		switch want {
+		case forceOpen:
+			b.ForceOpen()
+		case forceClose:
+			b.ForceClose()
+		case forceNone:
+			b.Reset()
+		}
+	}
+}
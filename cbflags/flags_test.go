@@ -0,0 +1,96 @@
+package cbflags
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// fakeProvider is an in-memory Provider, per the package's cbredis-style
+// interface contract.
+type fakeProvider struct {
+	mu    sync.Mutex
+	bools map[string]bool
+	ints  map[string]int
+}
+
+func (p *fakeProvider) Bool(_ context.Context, key string, def bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if v, ok := p.bools[key]; ok {
+		return v
+	}
+	return def
+}
+
+func (p *fakeProvider) Int(_ context.Context, key string, def int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if v, ok := p.ints[key]; ok {
+		return v
+	}
+	return def
+}
+
+func (p *fakeProvider) set(key string, v bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bools[key] = v
+}
+
+func TestControllerAppliesForceFlags(t *testing.T) {
+	provider := &fakeProvider{bools: map[string]bool{}, ints: map[string]int{}}
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{OpenTimeout: time.Minute})
+	b := registry.Get("payments")
+	c := NewController(provider, registry)
+
+	provider.set("cb.payments.force-open", true)
+	c.Apply(context.Background())
+	if got := b.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state after force-open flag = %v, want open", got)
+	}
+
+	// Clearing the flag reverts the force.
+	provider.set("cb.payments.force-open", false)
+	c.Apply(context.Background())
+	if got := b.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state after flag cleared = %v, want closed", got)
+	}
+}
+
+func TestControllerApplyIsIdempotent(t *testing.T) {
+	provider := &fakeProvider{bools: map[string]bool{}, ints: map[string]int{}}
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{OpenTimeout: time.Minute})
+	b := registry.Get("payments")
+	c := NewController(provider, registry)
+
+	// Repeated Apply with unchanged flags must not keep resetting the
+	// breaker: trip it organically and verify Apply leaves it alone.
+	registry.Get("payments") // ensure created
+	for i := 0; i < 6; i++ {
+		b.Do(func() error { return errors.New("boom") })
+	}
+	c.Apply(context.Background())
+	c.Apply(context.Background())
+	if got := b.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (Apply must not reset an organic trip)", got)
+	}
+}
+
+func TestControllerReadyToTripReadsFlag(t *testing.T) {
+	provider := &fakeProvider{bools: map[string]bool{}, ints: map[string]int{"cb.payments.failure-threshold": 2}}
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{})
+	c := NewController(provider, registry)
+
+	trip := c.ReadyToTrip("payments", 5)
+	if trip(circuitbreaker.Counts{ConsecutiveFailures: 1}) {
+		t.Fatal("tripped below the flagged threshold")
+	}
+	if !trip(circuitbreaker.Counts{ConsecutiveFailures: 2}) {
+		t.Fatal("did not trip at the flagged threshold")
+	}
+}
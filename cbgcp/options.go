@@ -0,0 +1,56 @@
+// Package cbgcp bundles the cbgrpc interceptors for Google Cloud clients
+// — Spanner, Pub/Sub, Firestore and friends — as ready-made dial and
+// client options, with a status-code classification tuned to how those
+// services use gRPC:
+//
+//   - Aborted is ignored: Spanner returns it for transaction contention,
+//     which the client library retries and which says nothing about the
+//     service's health;
+//   - ResourceExhausted counts: on GCP it means quota, and a circuit
+//     shedding load is the right response to a quota wall;
+//   - the rest follows cbgrpc's defaults.
+//
+// Any cbgrpc option can be appended, including overrides of this
+// classification:
+//
+//	client, err := spanner.NewClient(ctx, db, cbgcp.ClientOptions(breaker)...)
+package cbgcp
+
+import (
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbgrpc"
+)
+
+// DialOptions returns the grpc.DialOptions installing the breaker
+// interceptors with the GCP classification; opts are applied after it,
+// so cbgrpc.WithCodesAsFailure / WithCodesAsIgnored can override.
+func DialOptions(breaker *circuitbreaker.Breaker, opts ...cbgrpc.Option) []grpc.DialOption {
+	all := append([]cbgrpc.Option{
+		cbgrpc.WithCodesAsFailure(codes.Unavailable, codes.DeadlineExceeded,
+			codes.Internal, codes.ResourceExhausted, codes.Unknown, codes.DataLoss),
+		cbgrpc.WithCodesAsIgnored(codes.Aborted),
+	}, opts...)
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(cbgrpc.UnaryClientInterceptor(breaker, all...)),
+		grpc.WithChainStreamInterceptor(cbgrpc.StreamClientInterceptor(breaker, all...)),
+	}
+}
+
+// ClientOptions is DialOptions packaged as option.ClientOptions, for
+// passing straight to a cloud client constructor. Note that long-lived
+// streams (Pub/Sub's StreamingPull, Firestore's Listen) record their
+// outcome only when the stream ends; pair the breaker with per-method
+// breakers via cbgrpc.WithMethodBreakers if unary health should not wait
+// on them.
+func ClientOptions(breaker *circuitbreaker.Breaker, opts ...cbgrpc.Option) []option.ClientOption {
+	dial := DialOptions(breaker, opts...)
+	out := make([]option.ClientOption, len(dial))
+	for i, d := range dial {
+		out[i] = option.WithGRPCDialOption(d)
+	}
+	return out
+}
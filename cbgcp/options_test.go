@@ -0,0 +1,66 @@
+package cbgcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbgrpc"
+)
+
+// invoke runs one RPC through intercept with an invoker replying code.
+func invoke(t *testing.T, intercept grpc.UnaryClientInterceptor, code codes.Code) {
+	t.Helper()
+	intercept(context.Background(), "/spanner.v1.Spanner/Commit", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(code, "synthetic")
+		})
+}
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+func TestDialOptionsInstallBothInterceptors(t *testing.T) {
+	opts := DialOptions(tripAfter(1))
+	if len(opts) != 2 {
+		t.Fatalf("DialOptions = %d options, want unary and stream interceptors", len(opts))
+	}
+}
+
+func TestClientOptionsWrapDialOptions(t *testing.T) {
+	opts := ClientOptions(tripAfter(1))
+	if len(opts) != 2 {
+		t.Fatalf("ClientOptions = %d options, want 2", len(opts))
+	}
+}
+
+func TestGCPClassificationAppliesToInterceptor(t *testing.T) {
+	// The bundle's classification is what matters: Aborted (Spanner
+	// contention) is neutral, ResourceExhausted (quota) counts. Exercised
+	// through the same interceptor constructor DialOptions uses.
+	breaker := tripAfter(1)
+	intercept := cbgrpc.UnaryClientInterceptor(breaker,
+		cbgrpc.WithCodesAsFailure(codes.Unavailable, codes.DeadlineExceeded,
+			codes.Internal, codes.ResourceExhausted, codes.Unknown, codes.DataLoss),
+		cbgrpc.WithCodesAsIgnored(codes.Aborted))
+
+	invoke(t, intercept, codes.Aborted)
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (Aborted is contention, not unhealth)", got)
+	}
+	invoke(t, intercept, codes.ResourceExhausted)
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (quota exhaustion counts)", got)
+	}
+}
@@ -0,0 +1,124 @@
+// Package cbgin adapts the circuit breaker middleware to gin. Wrapping a
+// gin engine as a plain http.Handler loses access to gin's context and
+// abort semantics; this adapter rejects through c.Abort, keys per-route
+// breakers by gin's route template, and records handler errors collected
+// in c.Errors against the breaker.
+package cbgin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbhttp"
+)
+
+// middleware carries the adapter's configuration.
+type middleware struct {
+	breaker      *circuitbreaker.Breaker
+	registry     *circuitbreaker.Registry
+	isSuccessful func(status int) bool
+	onOpen       gin.HandlerFunc
+	observer     cbhttp.MiddlewareObserver
+}
+
+// Option configures the middleware.
+type Option func(*middleware)
+
+// WithRouteBreakers keys the middleware by gin's route template
+// (c.FullPath(), e.g. "GET /v1/users/:id") instead of one breaker for the
+// whole engine. Breakers come from registry, created on demand with its
+// defaults; requests matching no route share the "unmatched" breaker.
+func WithRouteBreakers(registry *circuitbreaker.Registry) Option {
+	return func(m *middleware) { m.registry = registry }
+}
+
+// WithSuccessfulStatus sets the predicate classifying a handler's
+// response status; the default treats anything below 500 as success.
+func WithSuccessfulStatus(fn func(status int) bool) Option {
+	return func(m *middleware) { m.isSuccessful = fn }
+}
+
+// WithOnCircuitOpen sets the handler invoked for requests shed by an open
+// circuit, replacing the default 503. It must write a response; the
+// request is aborted either way.
+func WithOnCircuitOpen(h gin.HandlerFunc) Option {
+	return func(m *middleware) { m.onOpen = h }
+}
+
+// WithObserver registers obs — e.g. prom.NewServerMetrics — for every
+// request the middleware sees, with gin's route template as the path.
+func WithObserver(obs cbhttp.MiddlewareObserver) Option {
+	return func(m *middleware) { m.observer = obs }
+}
+
+// Middleware returns a gin.HandlerFunc guarding handlers with breaker.
+// The breaker may be nil if WithRouteBreakers is used.
+func Middleware(breaker *circuitbreaker.Breaker, opts ...Option) gin.HandlerFunc {
+	m := &middleware{
+		breaker:      breaker,
+		isSuccessful: func(status int) bool { return status < 500 },
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return func(c *gin.Context) {
+		b := m.breakerFor(c)
+		done, err := b.AllowContext(c.Request.Context())
+		if err != nil {
+			if m.observer != nil {
+				m.observer.ObserveShed(c.Request.Method, routePath(c), cbhttp.ShedReason(err))
+			}
+			if m.onOpen != nil {
+				m.onOpen(c)
+				c.Abort()
+				return
+			}
+			c.Header("Retry-After", cbhttp.RetryAfterValue(b))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "service temporarily unavailable",
+			})
+			return
+		}
+		start := time.Now()
+		c.Next()
+		status := c.Writer.Status()
+		if m.observer != nil {
+			m.observer.ObserveHandled(c.Request.Method, routePath(c), status, time.Since(start))
+		}
+		if c.Request.Context().Err() != nil {
+			// Client went away mid-request; don't let aborts trip the
+			// breaker (see cbhttp.HTTPMiddleware).
+			done(nil)
+			return
+		}
+		if m.isSuccessful(status) {
+			done(nil)
+			return
+		}
+		if last := c.Errors.Last(); last != nil {
+			done(last.Err)
+			return
+		}
+		done(&cbhttp.StatusError{StatusCode: status, Status: http.StatusText(status)})
+	}
+}
+
+func (m *middleware) breakerFor(c *gin.Context) *circuitbreaker.Breaker {
+	if m.registry != nil {
+		return m.registry.Get(c.Request.Method + " " + routePath(c))
+	}
+	return m.breaker
+}
+
+// routePath returns gin's route template for the request, "unmatched"
+// when no route matched — raw paths would make the breaker and metrics
+// keys unbounded.
+func routePath(c *gin.Context) string {
+	if path := c.FullPath(); path != "" {
+		return path
+	}
+	return "unmatched"
+}
@@ -0,0 +1,106 @@
+package cbgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+func TestMiddlewareTripsAndSheds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	breaker := tripAfter(1)
+	handled := 0
+
+	engine := gin.New()
+	engine.Use(Middleware(breaker))
+	engine.GET("/work", func(c *gin.Context) {
+		handled++
+		c.Status(http.StatusInternalServerError)
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/work", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/work", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status while open = %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("shed response missing Retry-After")
+	}
+	if handled != 1 {
+		t.Fatalf("handler ran %d times, want 1", handled)
+	}
+}
+
+func TestMiddlewareRecordsHandlerErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	breaker := tripAfter(1)
+
+	engine := gin.New()
+	engine.Use(Middleware(breaker))
+	engine.GET("/work", func(c *gin.Context) {
+		c.Error(circuitbreaker.ErrOpen) // arbitrary error collected in c.Errors
+		c.Status(http.StatusBadGateway)
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/work", nil))
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (handler error recorded as failure)", got)
+	}
+	samples := breaker.Status().RecentErrors
+	if len(samples) == 0 {
+		t.Fatal("no failure sample recorded from c.Errors")
+	}
+}
+
+func TestMiddlewareRouteBreakers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+
+	engine := gin.New()
+	engine.Use(Middleware(nil, WithRouteBreakers(registry)))
+	engine.GET("/bad/:id", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+	engine.GET("/good", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bad/1", nil))
+
+	// Breakers key on the route template, so /bad/2 shares /bad/1's open
+	// breaker while /good is untouched.
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bad/2", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/bad/2 status = %d, want 503", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/good", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/good status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,147 @@
+// Package cbgoredis runs go-redis commands through a circuit breaker via
+// a redis.Hook. It is the go-redis-native sibling of cbredis (which backs
+// breaker state with Redis and deliberately avoids the dependency): here
+// the dependency is the point, so commands, pipelines and dials are
+// admitted through the breaker and a dead Redis fails fast — optionally
+// as a cache miss — instead of stalling every request on dial timeouts.
+package cbgoredis
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Hook implements redis.Hook; register it with client.AddHook.
+type Hook struct {
+	breaker  *circuitbreaker.Breaker
+	registry *circuitbreaker.Registry
+	fallback func(ctx context.Context, cmd redis.Cmder) error
+}
+
+// Option configures the hook.
+type Option func(*Hook)
+
+// WithCommandBreakers keys the hook by command name ("get", "hset", ...)
+// instead of one breaker for the whole client, so e.g. a Lua script
+// timing out doesn't open the circuit for plain GETs. Breakers come from
+// registry, created on demand with its defaults; pipelines share the
+// "pipeline" breaker.
+func WithCommandBreakers(registry *circuitbreaker.Registry) Option {
+	return func(h *Hook) { h.registry = registry }
+}
+
+// WithFallback invokes fn for commands shed by an open circuit instead of
+// returning the breaker error. fn may answer from a local cache or set a
+// substitute error on cmd.
+func WithFallback(fn func(ctx context.Context, cmd redis.Cmder) error) Option {
+	return func(h *Hook) { h.fallback = fn }
+}
+
+// WithOpenAsMiss makes shed commands fail with redis.Nil, so cache-aside
+// code paths treat an open circuit exactly like a cache miss and fall
+// through to the source of truth. Only appropriate when every guarded
+// command is a cache read; writes shed this way are silently lost.
+func WithOpenAsMiss() Option {
+	return WithFallback(func(context.Context, redis.Cmder) error { return redis.Nil })
+}
+
+// NewHook returns a Hook guarding commands with breaker. The breaker may
+// be nil if WithCommandBreakers is used.
+func NewHook(breaker *circuitbreaker.Breaker, opts ...Option) *Hook {
+	h := &Hook{breaker: breaker}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// DialHook implements redis.Hook.
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		b := h.breakerFor("dial")
+		done, err := b.AllowContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			done(err)
+			return nil, err
+		}
+		done(nil)
+		return conn, nil
+	}
+}
+
+// ProcessHook implements redis.Hook.
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		b := h.breakerFor(cmd.Name())
+		done, err := b.AllowContext(ctx)
+		if err != nil {
+			if h.fallback != nil {
+				return h.fallback(ctx, cmd)
+			}
+			return err
+		}
+		err = next(ctx, cmd)
+		done(classify(ctx, err))
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook.
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		b := h.breakerFor("pipeline")
+		done, err := b.AllowContext(ctx)
+		if err != nil {
+			if h.fallback != nil {
+				for _, cmd := range cmds {
+					if fbErr := h.fallback(ctx, cmd); fbErr != nil {
+						cmd.SetErr(fbErr)
+					}
+				}
+				return nil
+			}
+			return err
+		}
+		err = next(ctx, cmds)
+		outcome := classify(ctx, err)
+		if outcome == nil {
+			for _, cmd := range cmds {
+				if cmdErr := classify(ctx, cmd.Err()); cmdErr != nil {
+					outcome = cmdErr
+					break
+				}
+			}
+		}
+		done(outcome)
+		return err
+	}
+}
+
+func (h *Hook) breakerFor(name string) *circuitbreaker.Breaker {
+	if h.registry != nil {
+		return h.registry.Get(name)
+	}
+	return h.breaker
+}
+
+// classify maps a command error to the breaker outcome: redis.Nil is a
+// perfectly healthy miss and caller cancellation says nothing about
+// Redis, so neither counts as a failure.
+func classify(ctx context.Context, err error) error {
+	switch {
+	case err == nil, errors.Is(err, redis.Nil):
+		return nil
+	case errors.Is(err, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+package cbgoredis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+var errConn = errors.New("connection refused")
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+// process builds a ProcessHook around a next that replies err, counting
+// calls, and runs one GET through it.
+func process(h *Hook, err error, calls *int) error {
+	next := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		*calls++
+		return err
+	})
+	return next(context.Background(), redis.NewStringCmd(context.Background(), "get", "k"))
+}
+
+func TestProcessHookTripsAndFailsFast(t *testing.T) {
+	breaker := tripAfter(2)
+	h := NewHook(breaker)
+	calls := 0
+
+	for i := 0; i < 2; i++ {
+		if err := process(h, errConn, &calls); !errors.Is(err, errConn) {
+			t.Fatalf("cmd %d = %v, want errConn", i, err)
+		}
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+	if err := process(h, nil, &calls); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("cmd while open = %v, want ErrOpen", err)
+	}
+	if calls != 2 {
+		t.Fatalf("next ran %d times, want 2 (open circuit must not reach it)", calls)
+	}
+}
+
+func TestProcessHookMissAndCancelAreNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	h := NewHook(breaker)
+	calls := 0
+
+	process(h, redis.Nil, &calls)
+	process(h, context.Canceled, &calls)
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (misses and cancellations are neutral)", got)
+	}
+	if got := breaker.Counts(); got.TotalSuccesses != 2 {
+		t.Fatalf("TotalSuccesses = %d, want 2", got.TotalSuccesses)
+	}
+}
+
+func TestOpenAsMissShedsAsCacheMiss(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	h := NewHook(breaker, WithOpenAsMiss())
+	calls := 0
+
+	if err := process(h, nil, &calls); !errors.Is(err, redis.Nil) {
+		t.Fatalf("shed cmd = %v, want redis.Nil", err)
+	}
+	if calls != 0 {
+		t.Fatal("shed command reached next")
+	}
+}
+
+func TestPipelineHookRecordsFirstCommandFailure(t *testing.T) {
+	breaker := tripAfter(1)
+	h := NewHook(breaker)
+
+	// The pipeline itself succeeds but one command inside it failed; the
+	// breaker must still see the failure.
+	next := h.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		cmds[1].SetErr(errConn)
+		return nil
+	})
+	cmds := []redis.Cmder{
+		redis.NewStringCmd(context.Background(), "get", "a"),
+		redis.NewStringCmd(context.Background(), "get", "b"),
+	}
+	if err := next(context.Background(), cmds); err != nil {
+		t.Fatalf("pipeline = %v, want nil", err)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (command error inside pipeline)", got)
+	}
+}
+
+func TestCommandBreakers(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	h := NewHook(nil, WithCommandBreakers(registry))
+
+	next := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == "evalsha" {
+			return errConn
+		}
+		return nil
+	})
+	next(context.Background(), redis.NewCmd(context.Background(), "evalsha", "sha"))
+	if err := next(context.Background(), redis.NewStringCmd(context.Background(), "get", "k")); err != nil {
+		t.Fatalf("get = %v, want nil (commands fail independently)", err)
+	}
+	if got := registry.Get("evalsha").State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("evalsha state = %v, want open", got)
+	}
+}
@@ -0,0 +1,70 @@
+package cbgrpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultFailureCodes are the codes counted as failures by default:
+// codes meaning the backend is in trouble. Codes meaning the caller asked
+// for something wrong (NotFound, InvalidArgument, ...) or gave up
+// (Canceled) do not count, for the same reason the HTTP integrations
+// don't count 4xx: they say nothing about the dependency's health.
+var defaultFailureCodes = codeSet(codes.Unavailable, codes.DeadlineExceeded,
+	codes.Internal, codes.ResourceExhausted, codes.Unknown, codes.DataLoss)
+
+// defaultIsSuccessful classifies an RPC error with defaultFailureCodes.
+func defaultIsSuccessful(err error) bool {
+	if err == nil {
+		return true
+	}
+	_, failure := defaultFailureCodes[status.Code(err)]
+	return !failure
+}
+
+func codeSet(cs ...codes.Code) map[codes.Code]struct{} {
+	set := make(map[codes.Code]struct{}, len(cs))
+	for _, code := range cs {
+		set[code] = struct{}{}
+	}
+	return set
+}
+
+// WithCodesAsFailure replaces the default failure code set: an RPC ending
+// in one of these codes counts against the breaker, every other code
+// counts as success. Teams that consider DeadlineExceeded noise or want
+// Aborted counted adjust the set here.
+func WithCodesAsFailure(cs ...codes.Code) Option {
+	return func(c *config) { c.failureCodes = codeSet(cs...) }
+}
+
+// WithCodesAsIgnored carves exceptions out of the failure set (default or
+// WithCodesAsFailure): RPCs ending in these codes never count against the
+// breaker, even if the failure set lists them. Ignored takes precedence.
+func WithCodesAsIgnored(cs ...codes.Code) Option {
+	return func(c *config) { c.ignoredCodes = codeSet(cs...) }
+}
+
+// buildClassifier resolves isSuccessful from the configured code sets;
+// with neither option set the default classifier is kept as-is.
+func (c *config) buildClassifier() {
+	if c.failureCodes == nil && c.ignoredCodes == nil {
+		return
+	}
+	failures := c.failureCodes
+	if failures == nil {
+		failures = defaultFailureCodes
+	}
+	ignored := c.ignoredCodes
+	c.isSuccessful = func(err error) bool {
+		if err == nil {
+			return true
+		}
+		code := status.Code(err)
+		if _, ok := ignored[code]; ok {
+			return true
+		}
+		_, failure := failures[code]
+		return !failure
+	}
+}
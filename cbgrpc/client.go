@@ -0,0 +1,131 @@
+package cbgrpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor admitting
+// each unary RPC through breaker; rejected calls fail fast with
+// codes.Unavailable. The breaker may be nil if WithMethodBreakers is
+// used.
+func UnaryClientInterceptor(breaker *circuitbreaker.Breaker, opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(breaker, opts...)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if c.excluded(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		if c.peers != nil {
+			return c.peerCall(ctx, method, req, reply, cc, invoker, callOpts)
+		}
+		b := c.breakerFor(method)
+		if c.admission != nil {
+			if err := c.admission.check(ctx, method, b); err != nil {
+				return err
+			}
+		}
+		done, err := b.AllowContext(ctx)
+		if c.annotate {
+			annotateSpan(ctx, b, err)
+		}
+		if err != nil {
+			if c.fallback != nil {
+				return c.fallback(ctx, method, req, reply)
+			}
+			return rejectionError(err)
+		}
+		start := time.Now()
+		err = invoker(ctx, method, req, reply, cc, callOpts...)
+		if c.isSuccessful(err) {
+			if c.admission != nil && err == nil {
+				c.admission.observe(method, time.Since(start))
+			}
+			done(nil)
+		} else {
+			done(err)
+		}
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor
+// admitting each stream through breaker; rejected streams fail fast with
+// codes.Unavailable. The returned stream is wrapped so the breaker
+// outcome covers the stream's life, not just its establishment: a RecvMsg
+// or SendMsg error records the failure, a clean io.EOF on RecvMsg records
+// success. A stream abandoned without reading to EOF records no outcome
+// until its context ends it. The breaker may be nil if WithMethodBreakers
+// is used.
+func StreamClientInterceptor(breaker *circuitbreaker.Breaker, opts ...Option) grpc.StreamClientInterceptor {
+	c := newConfig(breaker, opts...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		b := c.breakerFor(method)
+		if b == nil {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+		done, err := b.AllowContext(ctx)
+		if c.annotate {
+			annotateSpan(ctx, b, err)
+		}
+		if err != nil {
+			return nil, rejectionError(err)
+		}
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			done(err)
+			return nil, err
+		}
+		return &outcomeStream{ClientStream: cs, done: done, isSuccessful: c.isSuccessful}, nil
+	}
+}
+
+// outcomeStream records the breaker outcome of a client stream when the
+// stream finishes: long-lived streams would otherwise never contribute
+// failures, because only their establishment was observed.
+type outcomeStream struct {
+	grpc.ClientStream
+	done         func(error)
+	isSuccessful func(error) bool
+	once         sync.Once
+}
+
+// finish records the stream's final outcome exactly once.
+func (s *outcomeStream) finish(err error) {
+	s.once.Do(func() {
+		if s.isSuccessful(err) {
+			s.done(nil)
+		} else {
+			s.done(err)
+		}
+	})
+}
+
+func (s *outcomeStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && err != io.EOF {
+		// io.EOF from SendMsg means the stream ended; the real status
+		// is surfaced by the next RecvMsg.
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *outcomeStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch err {
+	case nil:
+	case io.EOF:
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+package cbgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+// invoker returns a grpc.UnaryInvoker replying err and counting calls.
+func invoker(err error, calls *int) grpc.UnaryInvoker {
+	return func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		*calls++
+		return err
+	}
+}
+
+func TestUnaryInterceptorTripsAndRejects(t *testing.T) {
+	breaker := tripAfter(2)
+	intercept := UnaryClientInterceptor(breaker)
+	calls := 0
+	down := status.Error(codes.Unavailable, "backend down")
+
+	for i := 0; i < 2; i++ {
+		if err := intercept(context.Background(), "/svc/Get", nil, nil, nil, invoker(down, &calls)); status.Code(err) != codes.Unavailable {
+			t.Fatalf("call %d = %v, want Unavailable", i, err)
+		}
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	// Rejections surface as Unavailable without reaching the invoker.
+	err := intercept(context.Background(), "/svc/Get", nil, nil, nil, invoker(down, &calls))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("rejected call = %v, want Unavailable", err)
+	}
+	if calls != 2 {
+		t.Fatalf("invoker ran %d times, want 2 (open circuit must not reach it)", calls)
+	}
+}
+
+func TestUnaryInterceptorCallerCodesAreNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	intercept := UnaryClientInterceptor(breaker)
+	calls := 0
+
+	err := intercept(context.Background(), "/svc/Get", nil, nil, nil,
+		invoker(status.Error(codes.NotFound, "no such user"), &calls))
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("call = %v, want NotFound", err)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (NotFound says nothing about the backend)", got)
+	}
+}
+
+func TestUnaryInterceptorCustomCodeSets(t *testing.T) {
+	breaker := tripAfter(1)
+	intercept := UnaryClientInterceptor(breaker,
+		WithCodesAsFailure(codes.Aborted), WithCodesAsIgnored(codes.Unavailable))
+	calls := 0
+
+	intercept(context.Background(), "/svc/Get", nil, nil, nil,
+		invoker(status.Error(codes.Unavailable, "down"), &calls))
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (Unavailable is ignored)", got)
+	}
+	intercept(context.Background(), "/svc/Get", nil, nil, nil,
+		invoker(status.Error(codes.Aborted, "conflict"), &calls))
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (Aborted is in the failure set)", got)
+	}
+}
+
+func TestUnaryInterceptorSkipMethods(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	intercept := UnaryClientInterceptor(breaker,
+		WithSkipMethods("/grpc.health.v1.Health/Check"))
+	calls := 0
+
+	if err := intercept(context.Background(), "/grpc.health.v1.Health/Check", nil, nil, nil,
+		invoker(nil, &calls)); err != nil {
+		t.Fatalf("skipped method = %v, want nil even while open", err)
+	}
+	if calls != 1 {
+		t.Fatal("skipped method did not reach the invoker")
+	}
+}
+
+func TestUnaryInterceptorMethodBreakers(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	intercept := UnaryClientInterceptor(nil, WithMethodBreakers(registry))
+	calls := 0
+
+	intercept(context.Background(), "/svc/Bad", nil, nil, nil,
+		invoker(status.Error(codes.Unavailable, "down"), &calls))
+	if err := intercept(context.Background(), "/svc/Good", nil, nil, nil,
+		invoker(nil, &calls)); err != nil {
+		t.Fatalf("/svc/Good = %v, want nil (methods fail independently)", err)
+	}
+	if got := registry.Get("/svc/Bad").State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("/svc/Bad state = %v, want open", got)
+	}
+}
+
+func TestUnaryInterceptorFallback(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	fellBack := false
+	intercept := UnaryClientInterceptor(breaker,
+		WithFallback(func(ctx context.Context, method string, req, reply any) error {
+			fellBack = true
+			return nil
+		}))
+	calls := 0
+
+	if err := intercept(context.Background(), "/svc/Get", nil, nil, nil, invoker(nil, &calls)); err != nil {
+		t.Fatalf("fallback call = %v, want nil", err)
+	}
+	if !fellBack || calls != 0 {
+		t.Fatalf("fellBack=%v calls=%d, want fallback without invoking", fellBack, calls)
+	}
+}
@@ -0,0 +1,102 @@
+package cbgrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// WithDeadlineAdmission rejects unary calls up front — with
+// codes.FailedPrecondition — when the remaining context deadline is
+// shorter than margin times the method's typical latency (an exponential
+// moving average over successful calls). A call admitted with 20ms left
+// against a 200ms dependency is doomed to time out, count as a failure
+// and push the breaker toward open; rejecting it immediately keeps doomed
+// traffic out of the failure rate. margin <= 0 means 1; raise it to
+// demand headroom beyond the average. Until a method has recorded a
+// latency, everything is admitted.
+//
+// Rejections are reported to the breaker's metrics under
+// circuitbreaker.ReasonDeadlineTooShort but do not count as breaker
+// failures.
+func WithDeadlineAdmission(margin float64) Option {
+	if margin <= 0 {
+		margin = 1
+	}
+	return func(c *config) {
+		c.admission = &deadlineAdmission{margin: margin, byMethod: make(map[string]*ewma)}
+	}
+}
+
+// deadlineAdmission tracks typical latency per method; see
+// WithDeadlineAdmission.
+type deadlineAdmission struct {
+	margin float64
+
+	mu       sync.Mutex
+	byMethod map[string]*ewma
+}
+
+// check rejects a call whose deadline cannot cover the method's typical
+// latency.
+func (a *deadlineAdmission) check(ctx context.Context, method string, b *circuitbreaker.Breaker) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	typical := a.tracker(method).typical()
+	if typical == 0 {
+		return nil
+	}
+	required := time.Duration(float64(typical) * a.margin)
+	if remaining := time.Until(deadline); remaining < required {
+		b.ReportRejection(circuitbreaker.ReasonDeadlineTooShort)
+		return status.Errorf(codes.FailedPrecondition,
+			"cbgrpc: %s of deadline left, typical latency is %s", remaining.Round(time.Millisecond), typical.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// observe feeds a successful call's duration into the method's average.
+func (a *deadlineAdmission) observe(method string, d time.Duration) {
+	a.tracker(method).observe(d)
+}
+
+func (a *deadlineAdmission) tracker(method string) *ewma {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t, ok := a.byMethod[method]
+	if !ok {
+		t = &ewma{}
+		a.byMethod[method] = t
+	}
+	return t
+}
+
+// ewma is an exponential moving average with alpha 1/8, seeded by the
+// first observation.
+type ewma struct {
+	mu    sync.Mutex
+	value time.Duration
+}
+
+func (e *ewma) observe(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.value == 0 {
+		e.value = d
+		return
+	}
+	e.value += (d - e.value) / 8
+}
+
+func (e *ewma) typical() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.value
+}
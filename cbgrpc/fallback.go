@@ -0,0 +1,20 @@
+package cbgrpc
+
+import "context"
+
+// Fallback produces a substitute reply for an RPC shed by an open
+// circuit. reply is the (typed, zero-valued) response message of the
+// call; populate it — typically by type-asserting to the concrete proto
+// message and filling defaults, or proto.Merge-ing a canned reply — and
+// return nil to hand the application a degraded answer, or return an
+// error to surface instead of the default codes.Unavailable.
+type Fallback func(ctx context.Context, method string, req, reply any) error
+
+// WithFallback invokes fn for unary calls shed by an open circuit instead
+// of surfacing codes.Unavailable — cached or default replies for read
+// paths whose callers can live with degraded data. Calls that were
+// attempted and failed are not its business: the application sees their
+// real errors.
+func WithFallback(fn Fallback) Option {
+	return func(c *config) { c.fallback = fn }
+}
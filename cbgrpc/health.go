@@ -0,0 +1,118 @@
+package cbgrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// healthPollInterval is how often Watch re-evaluates breaker states.
+const healthPollInterval = time.Second
+
+// HealthServer implements grpc_health_v1.HealthServer from circuit
+// breaker states: a service whose associated breakers include an open one
+// reports NOT_SERVING, so gRPC clients and load balancers using standard
+// health checking route away from an instance whose dependency is down
+// without any custom signaling. Half-open breakers report SERVING —
+// probes are flowing and pulling traffic away would starve them of
+// evidence.
+type HealthServer struct {
+	healthpb.UnimplementedHealthServer
+
+	mu       sync.RWMutex
+	services map[string][]*circuitbreaker.Breaker
+	registry *circuitbreaker.Registry // see AssociateRegistry
+}
+
+// NewHealthServer returns an empty HealthServer; associate services with
+// breakers via Associate, then register it with
+// grpc_health_v1.RegisterHealthServer.
+func NewHealthServer() *HealthServer {
+	return &HealthServer{services: make(map[string][]*circuitbreaker.Breaker)}
+}
+
+// Associate ties a service name (as health checkers will query it; "" is
+// the server-wide service) to the breakers that gate it. Calling it again
+// for the same service appends.
+func (h *HealthServer) Associate(service string, breakers ...*circuitbreaker.Breaker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.services[service] = append(h.services[service], breakers...)
+}
+
+// AssociateRegistry ties the server-wide service ("") to every breaker in
+// registry, current and future — the common case of "this instance is
+// unhealthy when any of its circuits is open".
+func (h *HealthServer) AssociateRegistry(registry *circuitbreaker.Registry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.registry = registry
+}
+
+// Check implements grpc_health_v1.HealthServer.
+func (h *HealthServer) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := h.status(req.GetService())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch implements grpc_health_v1.HealthServer by polling the breakers
+// and sending a message on every status change.
+func (h *HealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	current, ok := h.status(req.GetService())
+	if !ok {
+		current = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+			next, ok := h.status(req.GetService())
+			if !ok {
+				next = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+			}
+			if next == current {
+				continue
+			}
+			current = next
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// status resolves a service's health; ok is false for unknown services.
+func (h *HealthServer) status(service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	h.mu.RLock()
+	breakers, ok := h.services[service]
+	registry := h.registry
+	h.mu.RUnlock()
+	if service == "" && registry != nil {
+		breakers = append(breakers, registry.All()...)
+		ok = true
+	}
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+	}
+	for _, b := range breakers {
+		if b.State() == circuitbreaker.StateOpen {
+			return healthpb.HealthCheckResponse_NOT_SERVING, true
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING, true
+}
@@ -0,0 +1,110 @@
+// Package cbgrpc integrates circuit breakers with gRPC: client and server
+// interceptors admitting each RPC through a breaker, so a dependency that
+// starts failing is shed with codes.Unavailable instead of tying up
+// connections and deadlines.
+package cbgrpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// config carries the interceptors' shared configuration.
+type config struct {
+	breaker      *circuitbreaker.Breaker
+	registry     *circuitbreaker.Registry
+	only         map[string]struct{}
+	skip         map[string]struct{}
+	isSuccessful func(err error) bool
+	failureCodes map[codes.Code]struct{}  // see WithCodesAsFailure
+	ignoredCodes map[codes.Code]struct{}  // see WithCodesAsIgnored
+	peers        *circuitbreaker.Registry // see WithPeerBreakers
+	fallback     Fallback                 // see WithFallback
+	admission    *deadlineAdmission       // see WithDeadlineAdmission
+	annotate     bool                     // see WithSpanAnnotations
+}
+
+// Option configures the interceptors.
+type Option func(*config)
+
+// WithMethodBreakers keys the interceptor by full method name (e.g.
+// "/users.v1.Users/GetUser") instead of one breaker for the whole
+// connection, so one failing RPC doesn't open the circuit for every RPC
+// on the ClientConn. Breakers come from registry, created on demand with
+// its defaults.
+func WithMethodBreakers(registry *circuitbreaker.Registry) Option {
+	return func(c *config) { c.registry = registry }
+}
+
+// WithOnlyMethods restricts the interceptor to the given full method
+// names; all other RPCs pass through without breaker accounting or
+// rejection.
+func WithOnlyMethods(methods ...string) Option {
+	return func(c *config) {
+		if c.only == nil {
+			c.only = make(map[string]struct{}, len(methods))
+		}
+		for _, method := range methods {
+			c.only[method] = struct{}{}
+		}
+	}
+}
+
+// WithSkipMethods excludes the given full method names from the
+// interceptor entirely — health checks and reflection being the usual
+// candidates, so they keep working while the circuit is open.
+func WithSkipMethods(methods ...string) Option {
+	return func(c *config) {
+		if c.skip == nil {
+			c.skip = make(map[string]struct{}, len(methods))
+		}
+		for _, method := range methods {
+			c.skip[method] = struct{}{}
+		}
+	}
+}
+
+func newConfig(breaker *circuitbreaker.Breaker, opts ...Option) *config {
+	c := &config{
+		breaker:      breaker,
+		isSuccessful: defaultIsSuccessful,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.buildClassifier()
+	return c
+}
+
+// excluded reports whether method is outside the interceptor's scope.
+func (c *config) excluded(method string) bool {
+	if _, ok := c.skip[method]; ok {
+		return true
+	}
+	if c.only != nil {
+		if _, ok := c.only[method]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// breakerFor returns the breaker guarding method, nil when the method is
+// excluded from the interceptor.
+func (c *config) breakerFor(method string) *circuitbreaker.Breaker {
+	if c.excluded(method) {
+		return nil
+	}
+	if c.registry != nil {
+		return c.registry.Get(method)
+	}
+	return c.breaker
+}
+
+// rejectionError converts a breaker admission error into the
+// codes.Unavailable status surfaced to the application.
+func rejectionError(err error) error {
+	return status.Error(codes.Unavailable, err.Error())
+}
@@ -0,0 +1,45 @@
+package cbgrpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbotel"
+)
+
+// WithSpanAnnotations annotates the span already active in the call's
+// context — the one otelgrpc starts when it sits in the interceptor chain
+// — with the breaker's name, state and admission decision, and attaches a
+// rejection event when a call is shed. Traces then show breaker-caused
+// Unavailable errors distinctly from real backend errors, instead of both
+// looking like a fast failure. Without a recording span the option is a
+// no-op.
+func WithSpanAnnotations() Option {
+	return func(c *config) { c.annotate = true }
+}
+
+// annotateSpan records the admission decision for b on the active span;
+// rejection is the admission error, nil for admitted calls.
+func annotateSpan(ctx context.Context, b *circuitbreaker.Breaker, rejection error) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	decision := "allowed"
+	if rejection != nil {
+		decision = "rejected"
+	}
+	span.SetAttributes(
+		cbotel.AttrBreakerName.String(b.Name()),
+		cbotel.AttrBreakerState.String(b.State().String()),
+		cbotel.AttrBreakerDecision.String(decision),
+	)
+	if rejection != nil {
+		span.AddEvent("circuitbreaker.rejected", trace.WithAttributes(
+			attribute.String("circuitbreaker.error", rejection.Error()),
+		))
+	}
+}
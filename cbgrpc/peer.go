@@ -0,0 +1,67 @@
+package cbgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// WithPeerBreakers keys breakers by the connected peer address instead of
+// per method or per connection, for ClientConns whose resolver returns
+// multiple addresses: a single bad backend then accumulates failures on
+// its own breaker instead of opening the circuit for the whole service.
+//
+// The peer is chosen by the balancer after the interceptor runs, so
+// admission is necessarily loose in this mode: calls are only rejected up
+// front when every peer seen so far has an open circuit (the service is
+// effectively down), and each call's outcome is recorded against the
+// breaker of the peer that actually served it. Per-peer breaker state is
+// most useful combined with health checking or outlier detection that
+// steers the balancer away from ejected backends.
+func WithPeerBreakers(registry *circuitbreaker.Registry) Option {
+	return func(c *config) { c.peers = registry }
+}
+
+// peerCall performs a unary call in per-peer mode; see WithPeerBreakers.
+func (c *config) peerCall(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, callOpts []grpc.CallOption) error {
+	if allOpen(c.peers) {
+		if c.fallback != nil {
+			return c.fallback(ctx, method, req, reply)
+		}
+		return rejectionError(circuitbreaker.ErrOpen)
+	}
+	var p peer.Peer
+	err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(&p))...)
+	if p.Addr != nil {
+		// Record, not Allow: admission in peer mode is the allOpen check
+		// above, and an Allow here would consume a second one — double-
+		// counting requests, burning half-open probe budget, and dropping
+		// the outcome whenever the peer breaker is open.
+		b := c.peers.Get(p.Addr.String())
+		if c.isSuccessful(err) {
+			b.Record(nil)
+		} else {
+			b.Record(err)
+		}
+	}
+	return err
+}
+
+// allOpen reports whether the registry knows at least one peer and every
+// one of them is open.
+func allOpen(registry *circuitbreaker.Registry) bool {
+	breakers := registry.All()
+	if len(breakers) == 0 {
+		return false
+	}
+	for _, b := range breakers {
+		if b.State() != circuitbreaker.StateOpen {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package cbgrpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 100 * time.Millisecond
+	defaultMaxBackoff    = 2 * time.Second
+)
+
+// RetryPolicy configures the retrying client interceptor. The zero value
+// retries Unavailable and ResourceExhausted up to 3 attempts with
+// exponential backoff from 100ms capped at 2s.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles per retry.
+	Backoff time.Duration
+	// MaxBackoff caps the doubling.
+	MaxBackoff time.Duration
+	// RetryableCodes lists the status codes worth retrying.
+	RetryableCodes []codes.Code
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = defaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.RetryableCodes == nil {
+		p.RetryableCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted}
+	}
+	return p
+}
+
+func (p RetryPolicy) retryable(code codes.Code) bool {
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryClientRetryInterceptor returns a grpc.UnaryClientInterceptor
+// combining retries with the breaker: every attempt is admitted
+// individually, so each failed attempt counts against the breaker and
+// retrying stops immediately — with codes.Unavailable — the moment the
+// circuit opens, instead of hammering a dependency that just tripped it.
+// Retries respect the call's deadline: no retry is started whose backoff
+// would not leave time for the attempt. The breaker may be nil if
+// WithMethodBreakers is used.
+func UnaryClientRetryInterceptor(breaker *circuitbreaker.Breaker, policy RetryPolicy, opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(breaker, opts...)
+	policy = policy.withDefaults()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		b := c.breakerFor(method)
+		if b == nil {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		backoff := policy.Backoff
+		var lastErr error
+		for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+			done, err := b.AllowContext(ctx)
+			if c.annotate {
+				annotateSpan(ctx, b, err)
+			}
+			if err != nil {
+				// The circuit opened (possibly under our own failed
+				// attempts); stop retrying at once.
+				if c.fallback != nil {
+					return c.fallback(ctx, method, req, reply)
+				}
+				return rejectionError(err)
+			}
+			err = invoker(ctx, method, req, reply, cc, callOpts...)
+			if c.isSuccessful(err) {
+				done(nil)
+			} else {
+				done(err)
+			}
+			if err == nil {
+				return nil
+			}
+			lastErr = err
+			if !policy.retryable(status.Code(err)) || attempt == policy.MaxAttempts-1 {
+				return err
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+				// Not enough deadline left for the backoff, let alone
+				// another attempt.
+				return err
+			}
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return status.FromContextError(ctx.Err()).Err()
+			case <-timer.C:
+			}
+			if backoff *= 2; backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+		}
+		return lastErr
+	}
+}
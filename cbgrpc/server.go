@@ -0,0 +1,74 @@
+package cbgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor admitting
+// each incoming unary RPC through breaker — the server-side analogue of
+// cbhttp.HTTPMiddleware, for handlers whose work depends on something
+// that can melt down. Shed RPCs are rejected with codes.Unavailable
+// before reaching the handler. The breaker may be nil if
+// WithMethodBreakers is used.
+func UnaryServerInterceptor(breaker *circuitbreaker.Breaker, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(breaker, opts...)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		b := c.breakerFor(info.FullMethod)
+		if b == nil {
+			return handler(ctx, req)
+		}
+		done, err := b.AllowContext(ctx)
+		if c.annotate {
+			annotateSpan(ctx, b, err)
+		}
+		if err != nil {
+			return nil, rejectionError(err)
+		}
+		resp, err := handler(ctx, req)
+		switch {
+		case ctx.Err() != nil:
+			// Caller went away mid-call; its error says nothing about
+			// this service's health.
+			done(nil)
+		case c.isSuccessful(err):
+			done(nil)
+		default:
+			done(err)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor
+// admitting each incoming stream through breaker; shed streams are
+// rejected with codes.Unavailable before reaching the handler. The
+// breaker may be nil if WithMethodBreakers is used.
+func StreamServerInterceptor(breaker *circuitbreaker.Breaker, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(breaker, opts...)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		b := c.breakerFor(info.FullMethod)
+		if b == nil {
+			return handler(srv, ss)
+		}
+		done, err := b.AllowContext(ss.Context())
+		if err != nil {
+			return rejectionError(err)
+		}
+		err = handler(srv, ss)
+		switch {
+		case ss.Context().Err() != nil:
+			done(nil)
+		case c.isSuccessful(err):
+			done(nil)
+		default:
+			done(err)
+		}
+		return err
+	}
+}
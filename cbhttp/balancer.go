@@ -0,0 +1,133 @@
+package cbhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// LoadBalancer spreads requests over multiple backends with a breaker per
+// backend, so one unhealthy replica or vendor endpoint is routed around
+// instead of failing a share of all traffic. Selection is round-robin;
+// when the chosen backend's circuit rejects the request — or the attempt
+// fails with a transport error and the body is replayable — the next
+// backend is tried, and only when every backend has been exhausted does
+// the caller see an error.
+type LoadBalancer struct {
+	// OnError, if set, receives backend discovery errors from Watch.
+	OnError func(error)
+
+	client   *HTTPClient
+	registry *circuitbreaker.Registry
+	next     atomic.Uint64
+
+	mu       sync.RWMutex
+	backends []*url.URL
+}
+
+// NewLoadBalancer returns a LoadBalancer over the backend base URLs.
+// Breakers are fetched from registry by backend host; opts configure the
+// underlying HTTPClient (retries, timeouts, classification, ...).
+func NewLoadBalancer(registry *circuitbreaker.Registry, backends []string, opts ...Option) (*LoadBalancer, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("cbhttp: no backends")
+	}
+	parsed := make([]*url.URL, len(backends))
+	for i, backend := range backends {
+		u, err := url.Parse(backend)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = u
+	}
+	opts = append(opts, WithPerHostBreakers(registry))
+	return &LoadBalancer{
+		client:   NewHTTPClient(nil, opts...),
+		registry: registry,
+		backends: parsed,
+	}, nil
+}
+
+// setBackends swaps in the new backend set and removes the breakers of
+// departed backends from the registry.
+func (lb *LoadBalancer) setBackends(backends []*url.URL) {
+	current := make(map[string]struct{}, len(backends))
+	for _, backend := range backends {
+		current[backend.Host] = struct{}{}
+	}
+	lb.mu.Lock()
+	previous := lb.backends
+	lb.backends = backends
+	lb.mu.Unlock()
+	for _, backend := range previous {
+		if _, ok := current[backend.Host]; !ok {
+			lb.registry.Remove(backend.Host)
+		}
+	}
+}
+
+// Backends returns the current backend base URLs.
+func (lb *LoadBalancer) Backends() []*url.URL {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	backends := make([]*url.URL, len(lb.backends))
+	copy(backends, lb.backends)
+	return backends
+}
+
+// Do sends req to one of the backends. The request's URL is taken as
+// relative to the chosen backend: its scheme and host are replaced, the
+// path and query kept.
+func (lb *LoadBalancer) Do(req *http.Request) (*http.Response, error) {
+	backends := lb.Backends()
+	if len(backends) == 0 {
+		return nil, errors.New("cbhttp: no backends")
+	}
+	start := lb.next.Add(1) - 1
+	var lastErr error
+	for i := 0; i < len(backends); i++ {
+		backend := backends[(start+uint64(i))%uint64(len(backends))]
+		attempt := req.Clone(req.Context())
+		attempt.URL.Scheme = backend.Scheme
+		attempt.URL.Host = backend.Host
+		attempt.Host = ""
+		if i > 0 {
+			var err error
+			if attempt, err = rewind(attempt); err != nil {
+				return nil, lastErr
+			}
+		}
+		resp, err := lb.client.Do(attempt)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if !rejected(err) && !replayable(req) {
+			// A real attempt consumed the body; without GetBody there
+			// is nothing left to fail over with.
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// Get issues a GET for path (plus optional query) against one of the
+// backends.
+func (lb *LoadBalancer) Get(path string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return lb.Do(req)
+}
+
+// rejected reports whether err is a breaker rejection rather than a
+// failed attempt.
+func rejected(err error) bool {
+	return errors.Is(err, circuitbreaker.ErrOpen) ||
+		errors.Is(err, circuitbreaker.ErrTooManyRequests)
+}
@@ -0,0 +1,67 @@
+package cbhttp
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+const (
+	// failureSnippetLimit caps how much of a failed response body is read
+	// into StatusError.Body.
+	failureSnippetLimit = 1 << 10
+
+	// drainLimit caps how much of an unwanted body is read before
+	// closing. Past the cap the connection is closed instead of reused —
+	// cheaper than reading megabytes just to keep a connection warm.
+	drainLimit = 4 << 10
+)
+
+// snippet reads up to failureSnippetLimit bytes of body and returns them
+// together with a replacement body that replays the read bytes before the
+// remainder, so capturing the snippet is invisible to the caller.
+func snippet(body io.ReadCloser) ([]byte, io.ReadCloser) {
+	buf := make([]byte, failureSnippetLimit)
+	n, _ := io.ReadFull(body, buf)
+	buf = buf[:n]
+	return buf, &replayBody{
+		Reader: io.MultiReader(bytes.NewReader(buf), body),
+		Closer: body,
+	}
+}
+
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// outcomeBody defers a success's breaker outcome until the caller
+// finishes with the body, so the recorded duration covers the read and a
+// connection cut mid-body still counts as a failure. Close before EOF
+// records success: the caller got what it wanted.
+type outcomeBody struct {
+	io.ReadCloser
+	done func(error)
+	once sync.Once
+}
+
+func (b *outcomeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	switch {
+	case err == io.EOF:
+		b.finish(nil)
+	case err != nil:
+		b.finish(err)
+	}
+	return n, err
+}
+
+func (b *outcomeBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+	return err
+}
+
+func (b *outcomeBody) finish(err error) {
+	b.once.Do(func() { b.done(err) })
+}
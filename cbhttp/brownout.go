@@ -0,0 +1,73 @@
+package cbhttp
+
+import (
+	"net/http"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Brownout is a degradation controller: it watches the breakers of shared
+// dependencies and, while any of them is unhealthy, disables the optional
+// endpoints registered with it so the capacity those dependencies have
+// left goes to core endpoints. "Optional" is a product decision —
+// recommendations, exports, search-as-you-type — encoded by wrapping
+// those handlers with Optional; core handlers are left unwrapped and
+// unaffected.
+type Brownout struct {
+	deps []*circuitbreaker.Breaker
+}
+
+// NewBrownout returns a Brownout watching the given dependency breakers.
+func NewBrownout(deps ...*circuitbreaker.Breaker) *Brownout {
+	return &Brownout{deps: deps}
+}
+
+// Degraded reports whether any watched dependency's circuit is open or
+// half-open — the signal that optional work should be shed.
+func (b *Brownout) Degraded() bool {
+	for _, dep := range b.deps {
+		if dep.State() != circuitbreaker.StateClosed {
+			return true
+		}
+	}
+	return false
+}
+
+// Optional wraps the handler of an optional endpoint. While degraded,
+// requests are served by lite — a cheap substitute such as a
+// StaticFallback or JSONFallback with an empty result — instead of next;
+// a nil lite sheds them with a 503 and a Retry-After derived from the
+// slowest-recovering dependency. Outside a brownout the wrapper is
+// pass-through.
+func (b *Brownout) Optional(next http.Handler, lite http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !b.Degraded() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if lite != nil {
+			lite.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", RetryAfterValue(b.slowest()))
+		http.Error(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+	})
+}
+
+// Enabled is the feature-flag view of the controller for optional work
+// that isn't an endpoint — background enrichment, cache warming — and is
+// simply the negation of Degraded.
+func (b *Brownout) Enabled() bool { return !b.Degraded() }
+
+// slowest returns the watched breaker furthest from half-open, whose
+// recovery time bounds the brownout's Retry-After.
+func (b *Brownout) slowest() *circuitbreaker.Breaker {
+	slowest := b.deps[0]
+	wait := slowest.UntilHalfOpen()
+	for _, dep := range b.deps[1:] {
+		if d := dep.UntilHalfOpen(); d > wait {
+			slowest, wait = dep, d
+		}
+	}
+	return slowest
+}
@@ -0,0 +1,153 @@
+package cbhttp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// maxCacheBody caps the size of a response body kept for stale fallback;
+// larger responses are passed through uncached.
+const maxCacheBody = 1 << 20
+
+// CachedResponse is the last good response stored for a GET, replayed
+// when the circuit is open.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Stored     time.Time
+}
+
+// ResponseCache is a pluggable store of last-good GET responses. The
+// in-memory implementation is NewMemoryCache; back it with Redis or
+// similar when stale fallback should survive restarts. Implementations
+// must be safe for concurrent use.
+type ResponseCache interface {
+	Load(key string) (CachedResponse, bool)
+	Store(key string, resp CachedResponse)
+}
+
+// WithStaleCache gives GETs graceful degradation: 2xx responses are
+// stored in cache, and while the circuit is open a cached copy is served
+// instead of an error, marked stale with Warning and X-Stale headers.
+// Only successful GET responses up to 1MB are cached.
+func WithStaleCache(cache ResponseCache) Option {
+	return func(c *HTTPClient) { c.cache = cache }
+}
+
+// NewMemoryCache returns an in-process ResponseCache bounded to
+// maxEntries responses, evicting the oldest entry when full.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, entries: make(map[string]CachedResponse)}
+}
+
+// MemoryCache is the in-process ResponseCache.
+type MemoryCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]CachedResponse
+}
+
+// Load implements ResponseCache.
+func (c *MemoryCache) Load(key string) (CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, ok := c.entries[key]
+	return resp, ok
+}
+
+// Store implements ResponseCache.
+func (c *MemoryCache) Store(key string, resp CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, v := range c.entries {
+			if oldestKey == "" || v.Stored.Before(oldest) {
+				oldestKey, oldest = k, v.Stored
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = resp
+}
+
+// staleResponse serves the cached copy for a GET rejected by an open
+// breaker, marked stale so callers and proxies can tell it apart from a
+// live response.
+func (c *HTTPClient) staleResponse(req *http.Request, rejection error) (*http.Response, bool) {
+	if c.cache == nil || req.Method != http.MethodGet || !errors.Is(rejection, circuitbreaker.ErrOpen) {
+		return nil, false
+	}
+	cached, ok := c.cache.Load(cacheKey(req))
+	if !ok {
+		return nil, false
+	}
+	header := make(http.Header, len(cached.Header)+3)
+	for k, v := range cached.Header {
+		header[k] = v
+	}
+	header.Set("Warning", `110 - "Response is Stale"`)
+	header.Set("X-Stale", "true")
+	header.Set("Age", strconv.Itoa(int(time.Since(cached.Stored).Seconds())))
+	return &http.Response{
+		StatusCode:    cached.StatusCode,
+		Status:        http.StatusText(cached.StatusCode),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}, true
+}
+
+// maybeCache stores a successful GET response for later stale fallback,
+// leaving the response readable by the caller.
+func (c *HTTPClient) maybeCache(req *http.Request, resp *http.Response) {
+	if c.cache == nil || req.Method != http.MethodGet ||
+		resp.StatusCode < 200 || resp.StatusCode >= 300 ||
+		resp.ContentLength > maxCacheBody {
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCacheBody+1))
+	if len(body) > maxCacheBody {
+		// Too large to cache, which we only learn mid-read when the
+		// length is unknown; splice the consumed prefix back onto the
+		// unread remainder so the caller still sees the whole body.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return
+	}
+	closeErr := resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || closeErr != nil {
+		return
+	}
+	header := make(http.Header, len(resp.Header))
+	for k, v := range resp.Header {
+		header[k] = v
+	}
+	c.cache.Store(cacheKey(req), CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       body,
+		Stored:     time.Now(),
+	})
+}
+
+func cacheKey(req *http.Request) string {
+	return req.URL.String()
+}
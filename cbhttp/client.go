@@ -0,0 +1,323 @@
+// Package cbhttp integrates circuit breakers with net/http: an HTTP client
+// whose outbound requests flow through a breaker, so a dependency that
+// starts failing is shed quickly instead of tying up connections.
+package cbhttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// HTTPClient is an HTTP client guarded by a circuit breaker. Requests are
+// admitted through the breaker and their outcomes recorded against it;
+// when the breaker is open, calls fail fast with circuitbreaker.ErrOpen
+// without touching the network.
+type HTTPClient struct {
+	breaker       *circuitbreaker.Breaker
+	client        *http.Client
+	isSuccessful  func(*http.Response) bool
+	drainFailures bool
+	debugHeaders  bool
+	observer      RequestObserver
+	streaming     func(*http.Request) bool
+
+	retry   *RetryPolicy  // nil when retries are disabled
+	hedge   *HedgePolicy  // nil when hedging is disabled
+	cache   ResponseCache // nil when stale fallback is disabled
+	flights *flightGroup  // nil when singleflight is disabled
+	shadow  *ShadowPolicy // nil when shadow probing is disabled
+
+	// registry and breakerKey select a breaker per request instead of
+	// the single breaker; see WithPerHostBreakers and
+	// WithPerEndpointBreakers.
+	registry   *circuitbreaker.Registry
+	breakerKey func(*http.Request) string
+
+	// construction-time settings, consumed by NewHTTPClient when no
+	// client is injected
+	transport http.RoundTripper
+	timeout   time.Duration
+}
+
+// Option configures an HTTPClient.
+type Option func(*HTTPClient)
+
+// WithIsSuccessful sets the predicate classifying a received response as a
+// success or a failure for the breaker. The default treats status codes
+// below 500 as success: 4xx means the caller's request was wrong, not that
+// the dependency is unhealthy.
+func WithIsSuccessful(fn func(resp *http.Response) bool) Option {
+	return func(c *HTTPClient) { c.isSuccessful = fn }
+}
+
+// WithHTTPClient replaces the default 30s-timeout client with client, so
+// the breaker wrapper composes with existing connection pooling, proxy and
+// TLS setup. The client is used as given: WithTransport and WithTimeout
+// are ignored when it is set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *HTTPClient) { c.client = client }
+}
+
+// WithTransport sets the transport (connection pool, mTLS, proxy) of the
+// client the HTTPClient builds, keeping the default timeout.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *HTTPClient) { c.transport = transport }
+}
+
+// WithTimeout sets the overall per-request timeout on the client the
+// HTTPClient builds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HTTPClient) { c.timeout = timeout }
+}
+
+// WithStreaming marks the requests for which the breaker outcome is
+// recorded as soon as response headers arrive. By default a success is
+// recorded when the caller finishes reading the body — the duration then
+// covers the transfer and a connection cut mid-body counts as a failure —
+// which is wrong for SSE streams and large downloads that are open-ended
+// by design.
+func WithStreaming(match func(req *http.Request) bool) Option {
+	return func(c *HTTPClient) { c.streaming = match }
+}
+
+// RequestObserver receives per-request telemetry from the client — host,
+// method, status — in addition to whatever the breaker's own metrics
+// collector records per breaker name, so dashboards can show which
+// upstream endpoints are failing. prom.NewClientMetrics provides a
+// cardinality-guarded Prometheus implementation. status is 0 when the
+// request failed without a response.
+type RequestObserver interface {
+	ObserveRequest(host, method string, status int, duration time.Duration)
+}
+
+// WithRequestObserver registers obs for every request the client sends.
+func WithRequestObserver(obs RequestObserver) Option {
+	return func(c *HTTPClient) { c.observer = obs }
+}
+
+// WithDrainFailures makes the client drain and close the body of every
+// failure-classified response itself, leaving resp.Body a closed reader
+// over the captured snippet. Fallback paths that never touch a failure
+// body then can't leak connections; leave this off when callers parse
+// error bodies longer than the snippet.
+func WithDrainFailures() Option {
+	return func(c *HTTPClient) { c.drainFailures = true }
+}
+
+// WithPerHostBreakers admits each request through a breaker for its host
+// instead of the client's single breaker, so one dead vendor doesn't
+// block requests to the others. Breakers are fetched from registry by
+// host (created on demand with the registry's defaults); when a client
+// can reach many hosts, pair the registry's metrics collector with
+// circuitbreaker.LimitCardinality.
+func WithPerHostBreakers(registry *circuitbreaker.Registry) Option {
+	return WithPerEndpointBreakers(registry, func(req *http.Request) string {
+		return req.URL.Host
+	})
+}
+
+// WithPerEndpointBreakers admits each request through a breaker for the
+// logical endpoint key returns — typically method plus route template,
+// e.g. "GET /v1/users/{id}" — so one broken endpoint doesn't open the
+// circuit for the whole API. Raw URL paths make poor keys (path
+// parameters explode cardinality); derive the key from the route template
+// your API client already knows. Breakers are fetched from registry by
+// key, created on demand with the registry's defaults.
+func WithPerEndpointBreakers(registry *circuitbreaker.Registry, key func(req *http.Request) string) Option {
+	return func(c *HTTPClient) {
+		c.registry = registry
+		c.breakerKey = key
+	}
+}
+
+// NewHTTPClient returns an HTTPClient sending requests through breaker,
+// with a 30s overall timeout. The breaker may be nil if
+// WithPerHostBreakers is used.
+func NewHTTPClient(breaker *circuitbreaker.Breaker, opts ...Option) *HTTPClient {
+	c := &HTTPClient{
+		breaker:      breaker,
+		timeout:      defaultTimeout,
+		isSuccessful: func(resp *http.Response) bool { return resp.StatusCode < 500 },
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.client == nil {
+		c.client = &http.Client{Timeout: c.timeout, Transport: c.transport}
+	}
+	return c
+}
+
+// StatusError is the failure recorded against the breaker when a response
+// arrived but its status code classified the request as failed. It is not
+// returned to callers — they get the response — but it appears in the
+// breaker's failure samples and implements HTTPStatus for the error
+// classifier.
+type StatusError struct {
+	StatusCode int
+	Status     string
+
+	// Body holds up to 1KB of the failed response's body, for error
+	// messages and failure samples; the full body stays readable on the
+	// response.
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	msg := "cbhttp: upstream returned " + e.Status
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// HTTPStatus returns the response status code; see
+// circuitbreaker.DefaultClassifyError.
+func (e *StatusError) HTTPStatus() int { return e.StatusCode }
+
+// Do sends req through the breaker, giving full http.Client parity:
+// headers, cookies and the request's context all behave as they would on a
+// plain client. The convenience helpers (Get, Post, ...) are wrappers over
+// Do.
+func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if c.flights != nil && req.Method == http.MethodGet {
+		return c.flights.do(req.Context(), cacheKey(req), func() (*http.Response, error) {
+			return c.do(req)
+		})
+	}
+	return c.do(req)
+}
+
+func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
+	breaker := c.breakerFor(req)
+	if c.shadow != nil && breaker.State() == circuitbreaker.StateHalfOpen {
+		// Shadow mode: recovery evidence comes from mirrored probes, and
+		// the caller degrades exactly as if the circuit were still open.
+		c.shadow.probe(breaker, req, c.client.Do, c.isSuccessful)
+		if stale, ok := c.staleResponse(req, circuitbreaker.ErrOpen); ok {
+			return stale, nil
+		}
+		return nil, circuitbreaker.ErrOpen
+	}
+	done, err := breaker.AllowContext(req.Context())
+	if err != nil {
+		if stale, ok := c.staleResponse(req, err); ok {
+			return stale, nil
+		}
+		return nil, err
+	}
+	if c.debugHeaders {
+		req.Header.Set(HeaderCircuitName, breaker.Name())
+		req.Header.Set(HeaderCircuitState, breaker.State().String())
+	}
+	start := time.Now()
+	resp, err := c.send(req)
+	if c.observer != nil {
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.observer.ObserveRequest(req.URL.Host, req.Method, status, time.Since(start))
+	}
+	if err != nil {
+		done(err)
+		return nil, err
+	}
+	if c.isSuccessful(resp) {
+		c.maybeCache(req, resp)
+		if c.streaming != nil && c.streaming(req) {
+			// Streaming endpoints record at header receipt: timing the
+			// whole body read would make every long-lived stream look
+			// like a slow call.
+			done(nil)
+		} else {
+			resp.Body = &outcomeBody{ReadCloser: resp.Body, done: done}
+		}
+	} else {
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+		body, rest := snippet(resp.Body)
+		statusErr.Body = string(body)
+		if c.drainFailures {
+			drainBody(rest)
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+		} else {
+			resp.Body = rest
+		}
+		done(statusErr)
+	}
+	if wait := retryAfter(resp); wait > 0 {
+		// Honor the server's own backpressure signal: if the failure
+		// just tripped the breaker, keep it open as long as asked.
+		breaker.HoldOpen(wait)
+	}
+	return resp, nil
+}
+
+// DoContext is Do with req rebound to ctx, so a caller's deadline and
+// cancellation propagate into the outbound request.
+func (c *HTTPClient) DoContext(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return c.Do(req.WithContext(ctx))
+}
+
+// GetContext issues a GET to url through the breaker under ctx.
+func (c *HTTPClient) GetContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// PostContext issues a POST to url through the breaker under ctx.
+func (c *HTTPClient) PostContext(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// breakerFor returns the breaker guarding req: the keyed breaker in
+// per-host or per-endpoint mode, the client's single breaker otherwise.
+func (c *HTTPClient) breakerFor(req *http.Request) *circuitbreaker.Breaker {
+	if c.registry != nil {
+		return c.registry.Get(c.breakerKey(req))
+	}
+	return c.breaker
+}
+
+// send dispatches an admitted request: hedged when hedging applies,
+// otherwise through the retry loop.
+func (c *HTTPClient) send(req *http.Request) (*http.Response, error) {
+	if c.hedge != nil && idempotentRequest(req) && replayable(req) {
+		return c.hedged(req)
+	}
+	return c.attempt(req)
+}
+
+// Get issues a GET to url through the breaker.
+func (c *HTTPClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post issues a POST to url through the breaker.
+func (c *HTTPClient) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
@@ -0,0 +1,121 @@
+package cbhttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func TestClientRecordsFailuresAndFailsFast(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	breaker := tripAfter(2)
+	client := NewHTTPClient(breaker)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get %d: %v (a 5xx is a response, not an error)", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("Get %d status = %d, want 500", i, resp.StatusCode)
+		}
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after two 500s", got)
+	}
+
+	if _, err := client.Get(server.URL); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Get while open = %v, want ErrOpen", err)
+	}
+	if hits != 2 {
+		t.Fatalf("server saw %d requests, want 2 (open circuit must not reach it)", hits)
+	}
+}
+
+func TestClientSuccessRecordedAtBodyClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	breaker := tripAfter(1)
+	client := NewHTTPClient(breaker)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	// The outcome covers the body read, so it lands at EOF/Close — not
+	// at header receipt.
+	if got := breaker.Counts(); got.TotalSuccesses != 0 {
+		t.Fatalf("TotalSuccesses before Close = %d, want 0", got.TotalSuccesses)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if got := breaker.Counts(); got.TotalSuccesses != 1 {
+		t.Fatalf("TotalSuccesses after Close = %d, want 1", got.TotalSuccesses)
+	}
+}
+
+func TestClientCustomIsSuccessful(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	breaker := tripAfter(1)
+	client := NewHTTPClient(breaker, WithIsSuccessful(func(resp *http.Response) bool {
+		return resp.StatusCode < 400
+	}))
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (429 is a failure under the custom classifier)", got)
+	}
+}
+
+func TestClientPerHostBreakers(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer good.Close()
+
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	client := NewHTTPClient(nil, WithPerHostBreakers(registry))
+
+	resp, err := client.Get(bad.URL)
+	if err != nil {
+		t.Fatalf("Get bad: %v", err)
+	}
+	resp.Body.Close()
+	if _, err := client.Get(bad.URL); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Get bad while open = %v, want ErrOpen", err)
+	}
+
+	resp, err = client.Get(good.URL)
+	if err != nil {
+		t.Fatalf("Get good = %v, want nil (hosts fail independently)", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,163 @@
+package cbhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Discoverer resolves the current set of backend base URLs for a
+// load-balanced service. Implementations are polled by
+// LoadBalancer.Watch; DNSSRVDiscoverer and ConsulDiscoverer are provided.
+type Discoverer interface {
+	Discover(ctx context.Context) ([]string, error)
+}
+
+// DNSSRVDiscoverer resolves backends from a DNS SRV record
+// (_service._proto.name), the standard mechanism in Kubernetes headless
+// services and Consul DNS.
+type DNSSRVDiscoverer struct {
+	// Service, Proto and Name form the SRV query per net.LookupSRV.
+	Service string
+	Proto   string
+	Name    string
+
+	// Scheme is prepended to each target ("http" if empty).
+	Scheme string
+
+	// Resolver overrides net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+// Discover implements Discoverer.
+func (d *DNSSRVDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	scheme := d.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	_, records, err := resolver.LookupSRV(ctx, d.Service, d.Proto, d.Name)
+	if err != nil {
+		return nil, err
+	}
+	backends := make([]string, 0, len(records))
+	for _, record := range records {
+		host := record.Target
+		if len(host) > 0 && host[len(host)-1] == '.' {
+			host = host[:len(host)-1]
+		}
+		backends = append(backends, fmt.Sprintf("%s://%s:%d", scheme, host, record.Port))
+	}
+	return backends, nil
+}
+
+// ConsulDiscoverer resolves the passing instances of a Consul service via
+// the agent's health API. It speaks plain HTTP to the agent, so no Consul
+// SDK dependency is required.
+type ConsulDiscoverer struct {
+	// Address is the agent's base URL, e.g. "http://127.0.0.1:8500".
+	Address string
+
+	// Service is the Consul service name to resolve.
+	Service string
+
+	// Scheme is prepended to each instance ("http" if empty).
+	Scheme string
+
+	// Client overrides http.DefaultClient for agent requests.
+	Client *http.Client
+}
+
+// Discover implements Discoverer.
+func (d *ConsulDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	scheme := d.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	endpoint := d.Address + "/v1/health/service/" + url.PathEscape(d.Service) + "?passing=1"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	var entries []struct {
+		Node struct {
+			Address string `json:"Address"`
+		} `json:"Node"`
+		Service struct {
+			Address string `json:"Address"`
+			Port    int    `json:"Port"`
+		} `json:"Service"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	backends := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+		backends = append(backends, fmt.Sprintf("%s://%s:%d", scheme, address, entry.Service.Port))
+	}
+	return backends, nil
+}
+
+// Watch keeps the balancer's backend set in sync with d, polling every
+// interval until ctx is canceled. Breakers for departed backends are
+// removed from the registry so their state and metrics don't linger.
+// Resolution errors keep the previous backend set and are reported to
+// OnError if set; run Watch on its own goroutine.
+func (lb *LoadBalancer) Watch(ctx context.Context, d Discoverer, interval time.Duration) {
+	lb.refresh(ctx, d)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lb.refresh(ctx, d)
+		}
+	}
+}
+
+func (lb *LoadBalancer) refresh(ctx context.Context, d Discoverer) {
+	backends, err := d.Discover(ctx)
+	if err != nil || len(backends) == 0 {
+		if err != nil && lb.OnError != nil {
+			lb.OnError(err)
+		}
+		return
+	}
+	parsed := make([]*url.URL, 0, len(backends))
+	for _, backend := range backends {
+		u, err := url.Parse(backend)
+		if err != nil {
+			if lb.OnError != nil {
+				lb.OnError(err)
+			}
+			return
+		}
+		parsed = append(parsed, u)
+	}
+	lb.setBackends(parsed)
+}
@@ -0,0 +1,110 @@
+package cbhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// WithOnCircuitOpen sets the handler invoked for requests shed by an open
+// circuit, replacing the default bare 503 — so APIs can degrade with a
+// useful body instead of an empty error. StaticFallback, JSONFallback and
+// TemplateFallback build common cases; WithLastGoodFallback serves cached
+// responses and takes precedence when it has one for the route.
+func WithOnCircuitOpen(h http.Handler) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.onOpen = h }
+}
+
+// StaticFallback returns a fallback handler writing a fixed body.
+func StaticFallback(status int, contentType string, body []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	})
+}
+
+// JSONFallback returns a fallback handler writing v marshaled as JSON;
+// it panics if v does not marshal, which a test of the handler setup
+// catches.
+func JSONFallback(status int, v any) http.Handler {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic("cbhttp: JSONFallback: " + err.Error())
+	}
+	return StaticFallback(status, "application/json", body)
+}
+
+// TemplateFallback returns a fallback handler rendering tmpl with the
+// rejected *http.Request as data.
+func TemplateFallback(status int, contentType string, tmpl *template.Template) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, r); err != nil {
+			http.Error(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// WithLastGoodFallback records the last successful GET response per route
+// (up to maxEntries routes, bodies capped at 1MB) and replays it, marked
+// stale, for requests shed by an open circuit — degraded data instead of
+// no data for read paths.
+func WithLastGoodFallback(maxEntries int) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.lastGood = NewMemoryCache(maxEntries) }
+}
+
+// fallbackKey keys the last-good cache: the route key in per-route mode,
+// method and path otherwise.
+func (m *HTTPMiddleware) fallbackKey(r *http.Request) string {
+	if m.routeKey != nil {
+		return m.routeKey(r)
+	}
+	return MethodPathKey(r)
+}
+
+// serveLastGood replays the cached response for the route, if any.
+func (m *HTTPMiddleware) serveLastGood(w http.ResponseWriter, r *http.Request) bool {
+	if m.lastGood == nil || r.Method != http.MethodGet {
+		return false
+	}
+	cached, ok := m.lastGood.Load(m.fallbackKey(r))
+	if !ok {
+		return false
+	}
+	header := w.Header()
+	for k, v := range cached.Header {
+		header[k] = v
+	}
+	header.Set("Warning", `110 - "Response is Stale"`)
+	header.Set("X-Stale", "true")
+	header.Set("Age", strconv.Itoa(int(time.Since(cached.Stored).Seconds())))
+	w.WriteHeader(cached.StatusCode)
+	_, _ = w.Write(cached.Body)
+	return true
+}
+
+// recordLastGood stores a captured successful GET response for the route.
+func (m *HTTPMiddleware) recordLastGood(r *http.Request, rw *responseWriter) {
+	if m.lastGood == nil || r.Method != http.MethodGet || rw.overflowed ||
+		rw.statusCode() < 200 || rw.statusCode() >= 300 {
+		return
+	}
+	header := make(http.Header, len(rw.Header()))
+	for k, v := range rw.Header() {
+		header[k] = v
+	}
+	m.lastGood.Store(m.fallbackKey(r), CachedResponse{
+		StatusCode: rw.statusCode(),
+		Header:     header,
+		Body:       append([]byte(nil), rw.captured...),
+		Stored:     time.Now(),
+	})
+}
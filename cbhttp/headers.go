@@ -0,0 +1,29 @@
+package cbhttp
+
+import "net/http"
+
+// Debug headers carrying breaker identity and state across service
+// boundaries, so a cascade of opens can be traced from response headers
+// alone. The client attaches them to outbound requests under
+// WithDebugHeaders; the server middleware attaches them to responses
+// under WithResponseDebugHeaders.
+const (
+	HeaderCircuitName  = "X-Circuit-Name"
+	HeaderCircuitState = "X-Circuit-State"
+)
+
+// WithDebugHeaders stamps every outbound request with the guarding
+// breaker's name and state, so the upstream's access logs show which
+// caller-side breaker a request went through and what it thought at the
+// time.
+func WithDebugHeaders() Option {
+	return func(c *HTTPClient) { c.debugHeaders = true }
+}
+
+// UpstreamCircuit reads the debug headers from an upstream response,
+// reporting the upstream's breaker name and state when it exposes them.
+func UpstreamCircuit(resp *http.Response) (name, state string, ok bool) {
+	name = resp.Header.Get(HeaderCircuitName)
+	state = resp.Header.Get(HeaderCircuitState)
+	return name, state, name != "" || state != ""
+}
@@ -0,0 +1,159 @@
+package cbhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultHedgeDelay = 50 * time.Millisecond
+	defaultMaxHedges  = 1
+)
+
+// HedgePolicy configures request hedging: when an attempt has not
+// completed within Delay, a duplicate is issued and whichever completes
+// first wins, cutting tail latency against a slow dependency. The whole
+// race records a single breaker outcome. Hedging applies only to
+// idempotent requests (or those carrying an IdempotencyKeyHeader) with
+// replayable bodies; everything else is sent once.
+type HedgePolicy struct {
+	// Delay is how long to wait before issuing the next duplicate.
+	// Defaults to 50ms; tune it to the dependency's upper-percentile
+	// latency so hedges fire only for genuinely slow requests.
+	Delay time.Duration
+
+	// MaxHedges is the number of duplicates allowed on top of the
+	// original request. Defaults to 1.
+	MaxHedges int
+}
+
+func (p HedgePolicy) withDefaults() HedgePolicy {
+	if p.Delay <= 0 {
+		p.Delay = defaultHedgeDelay
+	}
+	if p.MaxHedges <= 0 {
+		p.MaxHedges = defaultMaxHedges
+	}
+	return p
+}
+
+// WithHedging enables hedged requests according to policy. Hedged
+// requests are sent directly, bypassing the retry policy: hedging already
+// covers the slow-or-lost case, and stacking retries under each duplicate
+// would multiply load on an unhealthy dependency.
+func WithHedging(policy HedgePolicy) Option {
+	p := policy.withDefaults()
+	return func(c *HTTPClient) { c.hedge = &p }
+}
+
+// idempotentRequest reports whether req is safe to issue more than once
+// concurrently: an idempotent method, or any method with an idempotency
+// key.
+func idempotentRequest(req *http.Request) bool {
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// hedged races up to MaxHedges+1 copies of req and returns the first
+// completion. Losers are canceled and their responses drained in the
+// background; the winner's per-attempt context is canceled when its body
+// is closed.
+func (c *HTTPClient) hedged(req *http.Request) (*http.Response, error) {
+	parent := req.Context()
+	results := make(chan hedgeResult, c.hedge.MaxHedges+1)
+	start := func() {
+		ctx, cancel := context.WithCancel(parent)
+		attempt := req.Clone(ctx)
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				results <- hedgeResult{err: err, cancel: cancel}
+				return
+			}
+			attempt.Body = body
+		}
+		go func() {
+			resp, err := c.client.Do(attempt)
+			results <- hedgeResult{resp: resp, err: err, cancel: cancel}
+		}()
+	}
+
+	timer := time.NewTimer(c.hedge.Delay)
+	defer timer.Stop()
+	start()
+	launched, finished := 1, 0
+	var firstErr error
+	for {
+		select {
+		case res := <-results:
+			finished++
+			if res.err == nil {
+				// Winner: abandon the rest and hand the caller a body
+				// that releases this attempt's context on Close.
+				go reap(results, launched-finished)
+				res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: res.cancel}
+				return res.resp, nil
+			}
+			res.cancel()
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			if finished == launched {
+				if launched > c.hedge.MaxHedges {
+					return nil, firstErr // every attempt failed
+				}
+				start() // all in-flight attempts failed early; hedge now
+				launched++
+			}
+		case <-timer.C:
+			if launched <= c.hedge.MaxHedges {
+				start()
+				launched++
+				timer.Reset(c.hedge.Delay)
+			}
+		case <-parent.Done():
+			go reap(results, launched-finished)
+			return nil, parent.Err()
+		}
+	}
+}
+
+type hedgeResult struct {
+	resp   *http.Response
+	err    error
+	cancel context.CancelFunc
+}
+
+// reap collects the remaining n results of a hedged race, canceling their
+// contexts and draining any response bodies.
+func reap(results <-chan hedgeResult, n int) {
+	for i := 0; i < n; i++ {
+		res := <-results
+		if res.err == nil {
+			drain(res.resp)
+		}
+		res.cancel()
+	}
+}
+
+// cancelBody is a response body that cancels its attempt's context once
+// closed, releasing the hedge's resources without cutting off the read.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
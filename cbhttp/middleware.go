@@ -0,0 +1,409 @@
+package cbhttp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// HTTPMiddleware wraps an http.Handler in a circuit breaker: requests are
+// admitted through the breaker, handler outcomes (5xx = failure) are
+// recorded against it, and while the circuit is open requests are shed
+// with a 503 before they reach the handler. Use it in front of handlers
+// whose work depends on something that can melt down — a database, an
+// upstream API — so overload fails fast instead of piling up.
+type HTTPMiddleware struct {
+	breaker      *circuitbreaker.Breaker
+	registry     *circuitbreaker.Registry
+	routeKey     func(*http.Request) string
+	isSuccessful func(status int) bool
+	onOpen       http.Handler       // see WithOnCircuitOpen
+	lastGood     *MemoryCache       // see WithLastGoodFallback
+	bulkhead     chan struct{}      // see WithBulkhead
+	observer     MiddlewareObserver // see WithMiddlewareObserver
+	priority     func(*http.Request) Priority
+	streaming    func(*http.Request) bool
+	skip         func(*http.Request) bool
+	skipPrefixes []string
+	debugHeaders bool // see WithResponseDebugHeaders
+}
+
+// MiddlewareOption configures an HTTPMiddleware.
+type MiddlewareOption func(*HTTPMiddleware)
+
+// WithRouteBreakers keys the middleware by route instead of using one
+// breaker for the whole handler tree, so one bad route doesn't take down
+// all routes. key maps each request to a route name — MethodPathKey for
+// small static route sets, or an extractor that returns the router's
+// route template (the chi and gorilla adapter packages provide these).
+// Breakers come from registry, created on demand with its defaults.
+func WithRouteBreakers(registry *circuitbreaker.Registry, key func(r *http.Request) string) MiddlewareOption {
+	return func(m *HTTPMiddleware) {
+		m.registry = registry
+		m.routeKey = key
+	}
+}
+
+// WithSkipFunc excludes requests matching skip from the middleware
+// entirely: no breaker accounting, no bulkhead, no rejection. Use it so
+// probes and CORS preflights keep working during an open circuit, e.g.
+//
+//	WithSkipFunc(func(r *http.Request) bool { return r.Method == http.MethodOptions })
+func WithSkipFunc(skip func(r *http.Request) bool) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.skip = skip }
+}
+
+// WithSkipPaths excludes requests whose path starts with any of the
+// given prefixes, the common cases being "/healthz" and "/metrics". It
+// composes with WithSkipFunc: a request is skipped if either matches.
+func WithSkipPaths(prefixes ...string) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.skipPrefixes = append(m.skipPrefixes, prefixes...) }
+}
+
+// WithBulkhead limits the handler to maxInFlight concurrent requests;
+// requests beyond the limit are shed with 429 and a short Retry-After.
+// During downstream slowness requests pile up long before failures trip
+// the breaker — the bulkhead caps that pile-up at a size the handler
+// survives. Shed requests are reported to the breaker's metrics as
+// rejections with reason "bulkhead_full".
+func WithBulkhead(maxInFlight int) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.bulkhead = make(chan struct{}, maxInFlight) }
+}
+
+// WithSuccessfulStatus sets the predicate classifying a handler's
+// response status; the default treats anything below 500 as success.
+func WithSuccessfulStatus(fn func(status int) bool) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.isSuccessful = fn }
+}
+
+// WithStreamingRoutes marks the requests whose responses are long-lived
+// by design — SSE, long-poll, open-ended downloads. For them the breaker
+// outcome is recorded as soon as response headers are written: the
+// default of recording when the handler returns would hold the breaker's
+// request slot for the stream's lifetime and make every stream look like
+// a minutes-long call. Streaming responses are also never captured for
+// WithLastGoodFallback.
+func WithStreamingRoutes(match func(r *http.Request) bool) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.streaming = match }
+}
+
+// WithResponseDebugHeaders stamps every response — served and shed alike
+// — with the guarding breaker's name and state, the server-side
+// counterpart of the client's WithDebugHeaders. Downstream callers read
+// them with UpstreamCircuit to tell a 503 from this breaker apart from
+// one the handler produced itself.
+func WithResponseDebugHeaders() MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.debugHeaders = true }
+}
+
+// MethodPathKey keys a request by method and raw URL path, e.g.
+// "GET /healthz". Suitable only for fixed route sets: path parameters
+// make the key unbounded — prefer a route-template extractor then.
+func MethodPathKey(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// NewMiddleware returns an HTTPMiddleware guarding handlers with breaker.
+// The breaker may be nil if WithRouteBreakers is used.
+func NewMiddleware(breaker *circuitbreaker.Breaker, opts ...MiddlewareOption) *HTTPMiddleware {
+	m := &HTTPMiddleware{
+		breaker:      breaker,
+		isSuccessful: func(status int) bool { return status < 500 },
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Wrap returns next guarded by the middleware's breaker(s).
+func (m *HTTPMiddleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.skipped(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		breaker := m.breakerFor(r)
+		if m.debugHeaders {
+			w.Header().Set(HeaderCircuitName, breaker.Name())
+			w.Header().Set(HeaderCircuitState, breaker.State().String())
+		}
+		priority := PriorityNormal
+		if m.priority != nil {
+			priority = m.priority(r)
+		}
+		if m.bulkhead != nil {
+			if m.priority != nil && !m.bulkheadHeadroom(priority) {
+				m.shedPriority(w, r, breaker)
+				return
+			}
+			select {
+			case m.bulkhead <- struct{}{}:
+				defer func() { <-m.bulkhead }()
+			default:
+				breaker.ReportRejection(circuitbreaker.ReasonBulkheadFull)
+				m.observeShed(r, circuitbreaker.ReasonBulkheadFull)
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many concurrent requests", http.StatusTooManyRequests)
+				return
+			}
+		}
+		if m.priority != nil && priority <= PriorityLow &&
+			breaker.State() == circuitbreaker.StateHalfOpen {
+			// Probe slots are scarce while half-open; keep them for
+			// interactive traffic.
+			m.shedPriority(w, r, breaker)
+			return
+		}
+		done, err := breaker.AllowContext(r.Context())
+		if err != nil {
+			m.observeShed(r, ShedReason(err))
+			m.reject(w, r)
+			return
+		}
+		rw, wrapped := wrapResponseWriter(w)
+		rw.capture = m.lastGood != nil && r.Method == http.MethodGet
+		start := time.Now()
+		if m.streaming != nil && m.streaming(r) {
+			m.serveStreaming(wrapped, rw, r, next, done, start)
+			return
+		}
+		next.ServeHTTP(wrapped, r)
+		if m.observer != nil {
+			m.observer.ObserveHandled(r.Method, r.URL.Path, rw.statusCode(), time.Since(start))
+		}
+		if r.Context().Err() != nil {
+			// The client went away mid-request. Whatever the handler
+			// wrote after that says nothing about this service's
+			// health, so record the outcome neutrally rather than let
+			// impatient clients trip the breaker.
+			done(nil)
+			return
+		}
+		if m.isSuccessful(rw.statusCode()) {
+			done(nil)
+			m.recordLastGood(r, rw)
+		} else {
+			done(&StatusError{StatusCode: rw.statusCode(), Status: http.StatusText(rw.statusCode())})
+		}
+	})
+}
+
+func (m *HTTPMiddleware) skipped(r *http.Request) bool {
+	if m.skip != nil && m.skip(r) {
+		return true
+	}
+	for _, prefix := range m.skipPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveStreaming runs a streaming route's handler with the outcome
+// recorded at header receipt (or when the handler returns without ever
+// writing, for completeness); see WithStreamingRoutes.
+func (m *HTTPMiddleware) serveStreaming(wrapped http.ResponseWriter, rw *responseWriter, r *http.Request, next http.Handler, done func(error), start time.Time) {
+	rw.capture = false // open-ended bodies are never buffered
+	var once sync.Once
+	record := func(status int) {
+		once.Do(func() {
+			if m.observer != nil {
+				m.observer.ObserveHandled(r.Method, r.URL.Path, status, time.Since(start))
+			}
+			if m.isSuccessful(status) {
+				done(nil)
+			} else {
+				done(&StatusError{StatusCode: status, Status: http.StatusText(status)})
+			}
+		})
+	}
+	rw.onHeader = record
+	next.ServeHTTP(wrapped, r)
+	record(rw.statusCode())
+}
+
+// shedPriority rejects a request shed by priority-aware admission; see
+// WithPriority.
+func (m *HTTPMiddleware) shedPriority(w http.ResponseWriter, r *http.Request, breaker *circuitbreaker.Breaker) {
+	breaker.ReportRejection(circuitbreaker.ReasonLowPriority)
+	m.observeShed(r, circuitbreaker.ReasonLowPriority)
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, "shed for priority", http.StatusTooManyRequests)
+}
+
+func (m *HTTPMiddleware) observeShed(r *http.Request, reason string) {
+	if m.observer != nil {
+		m.observer.ObserveShed(r.Method, r.URL.Path, reason)
+	}
+}
+
+// ShedReason maps a breaker admission error to the rejection reason
+// reported for it, for the middleware and the framework adapters.
+func ShedReason(err error) string {
+	if errors.Is(err, circuitbreaker.ErrTooManyRequests) {
+		return circuitbreaker.ReasonTooManyRequests
+	}
+	return circuitbreaker.ReasonOpen
+}
+
+func (m *HTTPMiddleware) breakerFor(r *http.Request) *circuitbreaker.Breaker {
+	if m.registry != nil {
+		return m.registry.Get(m.routeKey(r))
+	}
+	return m.breaker
+}
+
+// reject writes the open-circuit response: a cached last-good response
+// when available, the configured fallback handler, or a 503 with a
+// Retry-After.
+func (m *HTTPMiddleware) reject(w http.ResponseWriter, r *http.Request) {
+	if m.serveLastGood(w, r) {
+		return
+	}
+	if m.onOpen != nil {
+		m.onOpen.ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Retry-After", RetryAfterValue(m.breakerFor(r)))
+	http.Error(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+}
+
+// RetryAfterValue derives a Retry-After header from the breaker's actual
+// time until half-open, rounded up to whole seconds. When the breaker has
+// no scheduled transition — rejections from half-open probe saturation,
+// or a breaker pinned with ForceOpen — it says 1 so clients retry soon
+// rather than backing off for a made-up duration. The middleware and the
+// framework adapter packages use it for their open-circuit responses.
+func RetryAfterValue(b *circuitbreaker.Breaker) string {
+	wait := b.UntilHalfOpen()
+	if wait <= 0 {
+		return "1"
+	}
+	seconds := int((wait + time.Second - 1) / time.Second)
+	return strconv.Itoa(seconds)
+}
+
+// responseWriter captures the status code written by the handler so the
+// outcome can be classified, and optionally the body for the last-good
+// fallback cache.
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	onHeader func(status int) // invoked once, when the status is first set
+
+	capture    bool
+	captured   []byte
+	overflowed bool // body exceeded maxCacheBody; don't cache it
+}
+
+// setStatus records the first status written; for streaming routes it
+// fires the header-time outcome recording.
+func (w *responseWriter) setStatus(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	if w.onHeader != nil {
+		w.onHeader(status)
+	}
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.setStatus(status)
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.setStatus(http.StatusOK)
+	if w.capture && !w.overflowed {
+		if len(w.captured)+len(p) > maxCacheBody {
+			w.overflowed = true
+			w.captured = nil
+		} else {
+			w.captured = append(w.captured, p...)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// statusCode returns the written status, 200 if the handler never wrote
+// one explicitly.
+func (w *responseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// wrapResponseWriter wraps w for status capture without hiding the
+// optional interfaces the underlying writer implements — asserting
+// http.Flusher (SSE), http.Hijacker (WebSocket upgrades), io.ReaderFrom
+// (sendfile) or http.Pusher on the wrapper answers exactly as w would.
+// It returns the capturing core and the wrapper to pass to the handler.
+func wrapResponseWriter(w http.ResponseWriter) (*responseWriter, http.ResponseWriter) {
+	rw := &responseWriter{ResponseWriter: w}
+	_, fl := w.(http.Flusher)
+	_, hj := w.(http.Hijacker)
+	_, rf := w.(io.ReaderFrom)
+	_, ps := w.(http.Pusher)
+	switch {
+	case fl && hj && rf: // HTTP/1 server connections
+		return rw, &flushHijackReaderFromWriter{rw}
+	case fl && ps: // HTTP/2 server connections
+		return rw, &flushPushWriter{rw}
+	case fl:
+		return rw, &flushWriter{rw}
+	default:
+		return rw, rw
+	}
+}
+
+type flushWriter struct{ *responseWriter }
+
+func (w *flushWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+type flushHijackReaderFromWriter struct{ *responseWriter }
+
+func (w *flushHijackReaderFromWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *flushHijackReaderFromWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (w *flushHijackReaderFromWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.setStatus(http.StatusOK)
+	if w.capture {
+		// Bodies streamed via sendfile aren't buffered for the
+		// last-good cache.
+		w.overflowed = true
+		w.captured = nil
+	}
+	return w.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+}
+
+type flushPushWriter struct{ *responseWriter }
+
+func (w *flushPushWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *flushPushWriter) Push(target string, opts *http.PushOptions) error {
+	return w.ResponseWriter.(http.Pusher).Push(target, opts)
+}
@@ -0,0 +1,175 @@
+package cbhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+func serve(t *testing.T, h http.Handler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestMiddlewareRecordsOutcomesAndTrips(t *testing.T) {
+	breaker := tripAfter(2)
+	handled := 0
+	h := NewMiddleware(breaker).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handled++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for i := 0; i < 2; i++ {
+		if rec := serve(t, h, http.MethodGet, "/"); rec.Code != http.StatusInternalServerError {
+			t.Fatalf("request %d status = %d, want 500", i, rec.Code)
+		}
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after two 500s", got)
+	}
+
+	rec := serve(t, h, http.MethodGet, "/")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status while open = %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("open-circuit response missing Retry-After")
+	}
+	if handled != 2 {
+		t.Fatalf("handler ran %d times, want 2 (open circuit must not reach it)", handled)
+	}
+}
+
+func TestMiddlewareSuccessIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	h := NewMiddleware(breaker).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	serve(t, h, http.MethodGet, "/missing")
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (a 404 is the client's problem)", got)
+	}
+	if got := breaker.Counts(); got.TotalSuccesses != 1 {
+		t.Fatalf("TotalSuccesses = %d, want 1", got.TotalSuccesses)
+	}
+}
+
+func TestMiddlewareWithSuccessfulStatus(t *testing.T) {
+	breaker := tripAfter(1)
+	h := NewMiddleware(breaker, WithSuccessfulStatus(func(status int) bool {
+		return status < 400
+	})).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	serve(t, h, http.MethodGet, "/missing")
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open (404 is a failure under the custom classifier)", got)
+	}
+}
+
+func TestMiddlewareSkipPaths(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	h := NewMiddleware(breaker, WithSkipPaths("/healthz")).Wrap(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	if rec := serve(t, h, http.MethodGet, "/healthz"); rec.Code != http.StatusOK {
+		t.Fatalf("health check status = %d, want 200 even while open", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodGet, "/work"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("guarded path status = %d, want 503", rec.Code)
+	}
+}
+
+func TestMiddlewareBulkheadOverflow(t *testing.T) {
+	breaker := tripAfter(100)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	h := NewMiddleware(breaker, WithBulkhead(1)).Wrap(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-release
+		}))
+
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		serve(t, h, http.MethodGet, "/")
+	}()
+	<-entered
+
+	rec := serve(t, h, http.MethodGet, "/")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("overflow status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("overflow Retry-After = %q, want \"1\"", got)
+	}
+	close(release)
+	<-firstDone
+
+	if got := breaker.Rejections(); got != 1 {
+		t.Fatalf("Rejections = %d, want 1 (bulkhead shed is a rejection)", got)
+	}
+}
+
+func TestMiddlewareResponseDebugHeaders(t *testing.T) {
+	breaker := circuitbreaker.New(circuitbreaker.Config{Name: "api", OpenTimeout: time.Minute})
+	h := NewMiddleware(breaker, WithResponseDebugHeaders()).Wrap(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := serve(t, h, http.MethodGet, "/")
+	if rec.Header().Get(HeaderCircuitName) != "api" || rec.Header().Get(HeaderCircuitState) != "closed" {
+		t.Fatalf("headers = %q/%q, want api/closed",
+			rec.Header().Get(HeaderCircuitName), rec.Header().Get(HeaderCircuitState))
+	}
+
+	// Shed responses carry them too — that's the point: the caller can
+	// tell this breaker's 503 from one the handler produced.
+	breaker.ForceOpen()
+	rec = serve(t, h, http.MethodGet, "/")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get(HeaderCircuitState) != "open" {
+		t.Fatalf("shed response = %d %q, want 503/open", rec.Code, rec.Header().Get(HeaderCircuitState))
+	}
+}
+
+func TestMiddlewareRouteBreakers(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	h := NewMiddleware(nil, WithRouteBreakers(registry, MethodPathKey)).Wrap(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/bad" {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+
+	serve(t, h, http.MethodGet, "/bad")
+	if rec := serve(t, h, http.MethodGet, "/bad"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/bad status = %d, want 503 (its route breaker is open)", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodGet, "/good"); rec.Code != http.StatusOK {
+		t.Fatalf("/good status = %d, want 200 (its route breaker is independent)", rec.Code)
+	}
+}
@@ -0,0 +1,26 @@
+package cbhttp
+
+import "time"
+
+// MiddlewareObserver receives per-request telemetry from HTTPMiddleware —
+// method and path for every request it handles or sheds — in addition to
+// whatever the breaker's own metrics collector records per breaker name,
+// so shed traffic can be alerted on per endpoint rather than per service.
+// prom.NewServerMetrics provides a cardinality-guarded Prometheus
+// implementation; implementations must normalize or bound the path label
+// themselves, since raw request paths are unbounded.
+type MiddlewareObserver interface {
+	// ObserveHandled is called for every request that reached the
+	// handler, with the response status and the handler's duration.
+	ObserveHandled(method, path string, status int, duration time.Duration)
+	// ObserveShed is called for every request rejected before the
+	// handler, with the rejection reason (circuitbreaker.ReasonOpen,
+	// ReasonTooManyRequests or ReasonBulkheadFull).
+	ObserveShed(method, path, reason string)
+}
+
+// WithMiddlewareObserver registers obs for every request the middleware
+// sees. Skipped requests (WithSkipFunc, WithSkipPaths) are not observed.
+func WithMiddlewareObserver(obs MiddlewareObserver) MiddlewareOption {
+	return func(m *HTTPMiddleware) { m.observer = obs }
+}
@@ -0,0 +1,72 @@
+package cbhttp
+
+import "net/http"
+
+// Priority ranks a request for admission when the middleware is shedding
+// load: low-priority traffic is shed first, so interactive requests beat
+// batch jobs for the capacity that remains.
+type Priority int
+
+const (
+	PriorityLow Priority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+// HeaderPriority is the request header the default extractor reads;
+// recognized values are "low" and "high" (case-sensitive), anything else
+// — including an absent header — is normal priority. Strip or validate
+// the header at the edge if clients setting their own priority is a
+// concern.
+const HeaderPriority = "X-Request-Priority"
+
+// HeaderPriorityExtractor derives a request's priority from the
+// HeaderPriority header.
+func HeaderPriorityExtractor(r *http.Request) Priority {
+	switch r.Header.Get(HeaderPriority) {
+	case "low":
+		return PriorityLow
+	case "high":
+		return PriorityHigh
+	}
+	return PriorityNormal
+}
+
+// WithPriority enables priority-aware admission using extract (nil means
+// HeaderPriorityExtractor). It changes shedding in two places:
+//
+//   - Bulkhead: low-priority requests are only admitted while the
+//     bulkhead is less than half full, and normal-priority requests leave
+//     a tenth of the slots (at least one) free for high priority — so a
+//     batch backfill can't starve interactive traffic of slots.
+//   - Half-open circuits: low-priority requests are shed without
+//     competing for the scarce probe slots.
+//
+// Requests shed for priority are rejected with 429 and reported under
+// circuitbreaker.ReasonLowPriority.
+func WithPriority(extract func(r *http.Request) Priority) MiddlewareOption {
+	if extract == nil {
+		extract = HeaderPriorityExtractor
+	}
+	return func(m *HTTPMiddleware) { m.priority = extract }
+}
+
+// bulkheadHeadroom reports whether a request of priority p may take a
+// bulkhead slot right now. The occupancy read and the slot acquisition
+// are not atomic, which can only shed slightly early — never exceed the
+// bulkhead.
+func (m *HTTPMiddleware) bulkheadHeadroom(p Priority) bool {
+	occupancy, capacity := len(m.bulkhead), cap(m.bulkhead)
+	switch {
+	case p <= PriorityLow:
+		return occupancy < capacity/2
+	case p >= PriorityHigh:
+		return true // bounded only by the bulkhead itself
+	default:
+		reserve := capacity / 10
+		if reserve < 1 {
+			reserve = 1
+		}
+		return occupancy < capacity-reserve
+	}
+}
@@ -0,0 +1,215 @@
+package cbhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ReverseProxy is an http.Handler proxying requests to a set of backends
+// with a circuit breaker per backend — an API-gateway building block on
+// top of httputil.ReverseProxy. Selection is round-robin; backends whose
+// circuit is open are skipped, a backend failing with a transport error
+// is retried on the next backend (when the request body is replayable),
+// and when every backend's circuit is open the configured fallback is
+// served instead of hammering dead backends.
+//
+// Response statuses are recorded against the chosen backend's breaker
+// (5xx = failure by default) but are passed through to the client as-is:
+// the first backend to produce a response wins.
+type ReverseProxy struct {
+	registry     *circuitbreaker.Registry
+	transport    http.RoundTripper
+	isSuccessful func(*http.Response) bool
+	fallback     http.Handler
+
+	mu       sync.RWMutex
+	backends []*url.URL
+	next     atomic.Uint64
+}
+
+// ReverseProxyOption configures a ReverseProxy.
+type ReverseProxyOption func(*ReverseProxy)
+
+// WithProxyTransport sets the RoundTripper used for proxied requests,
+// http.DefaultTransport if unset.
+func WithProxyTransport(transport http.RoundTripper) ReverseProxyOption {
+	return func(p *ReverseProxy) { p.transport = transport }
+}
+
+// WithProxyIsSuccessful sets the predicate classifying backend responses
+// for the breakers; the default treats status codes below 500 as success.
+func WithProxyIsSuccessful(fn func(resp *http.Response) bool) ReverseProxyOption {
+	return func(p *ReverseProxy) { p.isSuccessful = fn }
+}
+
+// WithProxyFallback sets the handler serving requests when every
+// backend's circuit is open, replacing the default 503. StaticFallback,
+// JSONFallback and TemplateFallback compose here as well.
+func WithProxyFallback(h http.Handler) ReverseProxyOption {
+	return func(p *ReverseProxy) { p.fallback = h }
+}
+
+// NewReverseProxy returns a ReverseProxy over the backend base URLs.
+// Breakers are fetched from registry by backend host, created on demand
+// with the registry's defaults.
+func NewReverseProxy(registry *circuitbreaker.Registry, backends []string, opts ...ReverseProxyOption) (*ReverseProxy, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("cbhttp: no backends")
+	}
+	parsed := make([]*url.URL, len(backends))
+	for i, backend := range backends {
+		u, err := url.Parse(backend)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = u
+	}
+	p := &ReverseProxy{
+		registry:     registry,
+		transport:    http.DefaultTransport,
+		isSuccessful: func(resp *http.Response) bool { return resp.StatusCode < 500 },
+		backends:     parsed,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// SetBackends swaps in a new backend set — e.g. from service discovery —
+// and removes the breakers of departed backends from the registry. An
+// empty set is rejected so a discovery hiccup doesn't empty the proxy.
+func (p *ReverseProxy) SetBackends(backends []string) error {
+	if len(backends) == 0 {
+		return errors.New("cbhttp: no backends")
+	}
+	parsed := make([]*url.URL, len(backends))
+	for i, backend := range backends {
+		u, err := url.Parse(backend)
+		if err != nil {
+			return err
+		}
+		parsed[i] = u
+	}
+	current := make(map[string]struct{}, len(parsed))
+	for _, backend := range parsed {
+		current[backend.Host] = struct{}{}
+	}
+	p.mu.Lock()
+	previous := p.backends
+	p.backends = parsed
+	p.mu.Unlock()
+	for _, backend := range previous {
+		if _, ok := current[backend.Host]; !ok {
+			p.registry.Remove(backend.Host)
+		}
+	}
+	return nil
+}
+
+// Backends returns the current backend base URLs.
+func (p *ReverseProxy) Backends() []*url.URL {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	backends := make([]*url.URL, len(p.backends))
+	copy(backends, p.backends)
+	return backends
+}
+
+// ServeHTTP implements http.Handler.
+func (p *ReverseProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	backends := p.Backends()
+	if len(backends) == 0 {
+		http.Error(w, "no backends available", http.StatusBadGateway)
+		return
+	}
+	start := p.next.Add(1) - 1
+	attempted := false
+	for i := 0; i < len(backends); i++ {
+		backend := backends[(start+uint64(i))%uint64(len(backends))]
+		breaker := p.registry.Get(backend.Host)
+		done, err := breaker.AllowContext(r.Context())
+		if err != nil {
+			continue
+		}
+		attempt := r
+		if attempted {
+			if attempt, err = rewind(r); err != nil {
+				// A real attempt consumed the body; without GetBody
+				// there is nothing left to fail over with.
+				done(err)
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+				return
+			}
+		}
+		attempted = true
+		if p.serveBackend(w, attempt, backend, done) {
+			return
+		}
+	}
+	if attempted {
+		// Every admitted backend failed with a transport error.
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return
+	}
+	p.rejectAll(w, r, backends)
+}
+
+// serveBackend proxies r to backend, recording the outcome through done.
+// It reports whether a response was written; false means a transport
+// error before any bytes reached the client, safe to retry elsewhere.
+func (p *ReverseProxy) serveBackend(w http.ResponseWriter, r *http.Request, backend *url.URL, done func(error)) bool {
+	var proxyErr error
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(backend)
+			pr.SetXForwarded()
+		},
+		Transport: p.transport,
+		ModifyResponse: func(resp *http.Response) error {
+			if p.isSuccessful(resp) {
+				done(nil)
+			} else {
+				done(&StatusError{StatusCode: resp.StatusCode, Status: resp.Status})
+			}
+			return nil
+		},
+		ErrorHandler: func(_ http.ResponseWriter, _ *http.Request, err error) {
+			// Swallow the default 502: the caller decides whether to
+			// fail over or give up.
+			proxyErr = err
+		},
+	}
+	proxy.ServeHTTP(w, r)
+	if proxyErr != nil {
+		done(proxyErr)
+		return false
+	}
+	return true
+}
+
+// rejectAll serves the all-circuits-open response: the configured
+// fallback, or a 503 with a Retry-After from the soonest-recovering
+// backend.
+func (p *ReverseProxy) rejectAll(w http.ResponseWriter, r *http.Request, backends []*url.URL) {
+	if p.fallback != nil {
+		p.fallback.ServeHTTP(w, r)
+		return
+	}
+	soonest := p.registry.Get(backends[0].Host)
+	wait := soonest.UntilHalfOpen()
+	for _, backend := range backends[1:] {
+		b := p.registry.Get(backend.Host)
+		if d := b.UntilHalfOpen(); d > 0 && (wait <= 0 || d < wait) {
+			soonest, wait = b, d
+		}
+	}
+	w.Header().Set("Retry-After", RetryAfterValue(soonest))
+	http.Error(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+}
@@ -0,0 +1,178 @@
+package cbhttp
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 100 * time.Millisecond
+	defaultMaxBackoff    = 2 * time.Second
+)
+
+// RetryPolicy configures opt-in retries inside the breaker: the whole
+// attempt sequence counts as one admitted request, so a transient 502
+// smoothed over by a retry records a success, while a dependency that
+// fails every attempt keeps recording failures and trips the circuit.
+// Zero fields take the documented defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first.
+	// Defaults to 3.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry; it doubles for each
+	// further retry, capped at MaxBackoff. Defaults to 100ms and 2s.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// RetryableStatuses are the response status codes worth retrying.
+	// Defaults to 429, 502, 503 and 504. Transport errors are always
+	// retried.
+	RetryableStatuses []int
+
+	// RetryableMethods are the request methods eligible for retry.
+	// Defaults to the idempotent methods — GET, HEAD, OPTIONS, TRACE,
+	// PUT and DELETE — so a POST is never silently duplicated.
+	// Regardless of method, a request carrying an IdempotencyKeyHeader
+	// is eligible: the key makes the write safe to replay.
+	RetryableMethods []string
+}
+
+// IdempotencyKeyHeader marks a request as safe to retry regardless of its
+// method. Callers set it to a unique key per logical operation (the
+// IETF Idempotency-Key convention) and the server deduplicates replays.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = defaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.RetryableStatuses == nil {
+		p.RetryableStatuses = []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+	if p.RetryableMethods == nil {
+		p.RetryableMethods = []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodOptions,
+			http.MethodTrace,
+			http.MethodPut,
+			http.MethodDelete,
+		}
+	}
+	return p
+}
+
+// retryableRequest reports whether req may be attempted more than once:
+// its method is in RetryableMethods or it carries an idempotency key.
+func (p RetryPolicy) retryableRequest(req *http.Request) bool {
+	if req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	for _, method := range p.RetryableMethods {
+		if req.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) retryableStatus(code int) bool {
+	for _, status := range p.RetryableStatuses {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRetry enables retries with backoff according to policy.
+func WithRetry(policy RetryPolicy) Option {
+	p := policy.withDefaults()
+	return func(c *HTTPClient) { c.retry = &p }
+}
+
+// attempt sends req, retrying per the client's retry policy. It is called
+// after the breaker admitted the request; the caller records one outcome
+// for the whole sequence.
+func (c *HTTPClient) attempt(req *http.Request) (*http.Response, error) {
+	if c.retry == nil || !c.retry.retryableRequest(req) || !replayable(req) {
+		return c.client.Do(req)
+	}
+	backoff := c.retry.Backoff
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			var err error
+			if req, err = rewind(req); err != nil {
+				return nil, err
+			}
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > c.retry.MaxBackoff {
+				backoff = c.retry.MaxBackoff
+			}
+		}
+		resp, err := c.client.Do(req)
+		last := attempt == c.retry.MaxAttempts-1
+		if err != nil {
+			if last {
+				return nil, err
+			}
+			continue
+		}
+		if last || !c.retry.retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		drain(resp)
+	}
+}
+
+// replayable reports whether req can be sent more than once: its body is
+// absent or can be re-materialized via GetBody. Non-replayable requests
+// get a single attempt.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// drain discards up to drainLimit bytes of a response body that won't be
+// read and closes it, so small bodies keep their connection reusable
+// without paying to read large ones.
+func drain(resp *http.Response) {
+	drainBody(resp.Body)
+}
+
+func drainBody(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, drainLimit))
+	_ = body.Close()
+}
+
+// rewind returns req with its body restored for another attempt.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
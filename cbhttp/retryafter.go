@@ -0,0 +1,38 @@
+package cbhttp
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryAfter caps how long an upstream Retry-After can hold a breaker
+// open; a mistyped header shouldn't bench a dependency for hours.
+const maxRetryAfter = 5 * time.Minute
+
+// retryAfter returns the wait requested by a 429 or 503 response's
+// Retry-After header — seconds or an HTTP date — or 0 when absent,
+// unparsable or not applicable.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests &&
+		resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	var wait time.Duration
+	if secs, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(value); err == nil {
+		wait = time.Until(t)
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return wait
+}
@@ -0,0 +1,104 @@
+package cbhttp
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+const (
+	defaultShadowSample  = 0.1
+	defaultShadowTimeout = 5 * time.Second
+)
+
+// ShadowPolicy configures shadow probing: while the breaker is half-open,
+// real requests are not sent to the recovering backend — the caller is
+// failed fast (or served from the stale cache / fallback) exactly as if
+// the circuit were still open — and instead a sample of them is mirrored
+// to the backend in the background, responses discarded, with the
+// mirrored outcomes recorded as the half-open probes. The breaker gathers
+// recovery evidence from real traffic shapes without exposing any user to
+// a backend that may still be down.
+//
+// Only replayable requests (no body, or GetBody set) with idempotent
+// methods are mirrored; a half-open period seeing none keeps rejecting
+// until one arrives.
+type ShadowPolicy struct {
+	// Sample is the fraction of eligible requests mirrored while
+	// half-open, in (0, 1]. Defaults to 0.1.
+	Sample float64
+	// Timeout bounds each mirrored request, independent of the
+	// originating caller's context. Defaults to 5s.
+	Timeout time.Duration
+}
+
+func (p ShadowPolicy) withDefaults() ShadowPolicy {
+	if p.Sample <= 0 || p.Sample > 1 {
+		p.Sample = defaultShadowSample
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = defaultShadowTimeout
+	}
+	return p
+}
+
+// WithShadowProbes enables shadow probing on the client; see
+// ShadowPolicy. It composes with WithStaleCache and half-open callers
+// then degrade identically to open-circuit callers.
+func WithShadowProbes(policy ShadowPolicy) Option {
+	return func(c *HTTPClient) {
+		p := policy.withDefaults()
+		c.shadow = &p
+	}
+}
+
+// WithTransportShadowProbes enables shadow probing on the transport; see
+// ShadowPolicy.
+func WithTransportShadowProbes(policy ShadowPolicy) TransportOption {
+	return func(t *Transport) {
+		p := policy.withDefaults()
+		t.shadow = &p
+	}
+}
+
+// probe mirrors req to the backend in the background when the sample
+// selects it: the probe is admitted through the breaker like any
+// half-open request, sent detached from the caller's context via send,
+// classified, and its response drained and discarded.
+func (p *ShadowPolicy) probe(breaker *circuitbreaker.Breaker, req *http.Request,
+	send func(*http.Request) (*http.Response, error), isSuccessful func(*http.Response) bool) {
+	if !idempotentRequest(req) || !replayable(req) {
+		return
+	}
+	if rand.Float64() >= p.Sample {
+		return
+	}
+	done, err := breaker.Allow()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	mirror, err := rewind(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		done(err)
+		return
+	}
+	go func() {
+		defer cancel()
+		resp, err := send(mirror)
+		if err != nil {
+			done(err)
+			return
+		}
+		if isSuccessful(resp) {
+			done(nil)
+		} else {
+			done(&StatusError{StatusCode: resp.StatusCode, Status: resp.Status})
+		}
+		drain(resp)
+	}()
+}
@@ -0,0 +1,92 @@
+package cbhttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WithSingleflight coalesces concurrent identical GETs into one upstream
+// call whose result is shared by every waiting caller. It cuts probe
+// pressure on a recovering dependency — in half-open, ten concurrent
+// reads become one probe instead of one probe and nine rejections. Shared
+// response bodies are buffered fully in memory, so this is for small read
+// paths, not downloads; the first caller's request performs the call and
+// its context governs the attempt.
+func WithSingleflight() Option {
+	return func(c *HTTPClient) {
+		c.flights = &flightGroup{calls: make(map[string]*flightCall)}
+	}
+}
+
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// do runs fn once per key among concurrent callers and hands each caller
+// its own copy of the shared result. A waiting caller whose own context
+// expires gets its context error instead of the shared result.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (*http.Response, error)) (*http.Response, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.response()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	resp, err := fn()
+	if err != nil {
+		call.err = err
+	} else {
+		call.status = resp.StatusCode
+		call.header = resp.Header
+		call.body, call.err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+	}
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+	return call.response()
+}
+
+// response materializes a fresh *http.Response over the shared result, so
+// each caller reads its own body.
+func (call *flightCall) response() (*http.Response, error) {
+	if call.err != nil {
+		return nil, call.err
+	}
+	header := make(http.Header, len(call.header))
+	for k, v := range call.header {
+		header[k] = v
+	}
+	return &http.Response{
+		StatusCode:    call.status,
+		Status:        http.StatusText(call.status),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(call.body)),
+		ContentLength: int64(len(call.body)),
+	}, nil
+}
@@ -0,0 +1,99 @@
+package cbhttp
+
+import (
+	"net/http"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Transport is an http.RoundTripper admitting every request through a
+// circuit breaker, for callers who want breaker protection on an existing
+// *http.Client (SDKs, generated clients) without adopting HTTPClient.
+// Rejections surface as circuitbreaker.ErrOpen / ErrTooManyRequests from
+// RoundTrip, wrapped in *url.Error by http.Client.
+type Transport struct {
+	breaker      *circuitbreaker.Breaker
+	base         http.RoundTripper
+	registry     *circuitbreaker.Registry
+	isSuccessful func(*http.Response) bool
+	skip         func(*http.Request) bool
+	shadow       *ShadowPolicy
+}
+
+// TransportOption configures a Transport.
+type TransportOption func(*Transport)
+
+// WithBase sets the RoundTripper actually performing requests,
+// http.DefaultTransport if unset.
+func WithBase(base http.RoundTripper) TransportOption {
+	return func(t *Transport) { t.base = base }
+}
+
+// WithHostBreakers shards the transport by request host: each host gets
+// its own breaker from registry (created on demand with the registry's
+// defaults), so one transport injected into one http.Client protects
+// many destinations independently instead of conflating them.
+func WithHostBreakers(registry *circuitbreaker.Registry) TransportOption {
+	return func(t *Transport) { t.registry = registry }
+}
+
+// WithTransportIsSuccessful sets the predicate classifying responses; the
+// default treats status codes below 500 as success.
+func WithTransportIsSuccessful(fn func(resp *http.Response) bool) TransportOption {
+	return func(t *Transport) { t.isSuccessful = fn }
+}
+
+// WithTransportFilter excludes requests matching skip from breaker
+// accounting and rejection entirely: they are passed straight to the base
+// transport. Synthetic traffic — health checks, metrics scrapes, CORS
+// preflights — otherwise distorts failure rates and gets rejected along
+// with real traffic while the circuit is open.
+func WithTransportFilter(skip func(req *http.Request) bool) TransportOption {
+	return func(t *Transport) { t.skip = skip }
+}
+
+// NewTransport returns a Transport guarding requests with breaker. The
+// breaker may be nil if WithHostBreakers is used.
+func NewTransport(breaker *circuitbreaker.Breaker, opts ...TransportOption) *Transport {
+	t := &Transport{
+		breaker:      breaker,
+		base:         http.DefaultTransport,
+		isSuccessful: func(resp *http.Response) bool { return resp.StatusCode < 500 },
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.skip != nil && t.skip(req) {
+		return t.base.RoundTrip(req)
+	}
+	breaker := t.breaker
+	if t.registry != nil {
+		breaker = t.registry.Get(req.URL.Host)
+	}
+	if t.shadow != nil && breaker.State() == circuitbreaker.StateHalfOpen {
+		// Shadow mode: recovery evidence comes from mirrored probes, and
+		// the caller fails fast exactly as if the circuit were still open.
+		t.shadow.probe(breaker, req, t.base.RoundTrip, t.isSuccessful)
+		return nil, circuitbreaker.ErrOpen
+	}
+	done, err := breaker.AllowContext(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil:
+		done(err)
+		return nil, err
+	case t.isSuccessful(resp):
+		done(nil)
+	default:
+		done(&StatusError{StatusCode: resp.StatusCode, Status: resp.Status})
+	}
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+package cbhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func TestTransportGuardsPlainClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	breaker := tripAfter(1)
+	client := &http.Client{Transport: NewTransport(breaker)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	// http.Client wraps transport errors in *url.Error; Is must still
+	// find the sentinel.
+	if _, err := client.Get(server.URL); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Get while open = %v, want ErrOpen", err)
+	}
+}
+
+func TestTransportFilterBypassesBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	client := &http.Client{Transport: NewTransport(breaker,
+		WithTransportFilter(func(req *http.Request) bool {
+			return req.URL.Path == "/healthz"
+		}))}
+
+	resp, err := client.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("filtered Get = %v, want nil even while open", err)
+	}
+	resp.Body.Close()
+	if got := breaker.Counts(); got.Requests != 0 {
+		t.Fatalf("Requests = %d, want 0 (filtered traffic is not accounted)", got.Requests)
+	}
+}
+
+func TestTransportHostBreakers(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer good.Close()
+
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	client := &http.Client{Transport: NewTransport(nil, WithHostBreakers(registry))}
+
+	resp, err := client.Get(bad.URL)
+	if err != nil {
+		t.Fatalf("Get bad: %v", err)
+	}
+	resp.Body.Close()
+	if _, err := client.Get(bad.URL); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Get bad while open = %v, want ErrOpen", err)
+	}
+	resp, err = client.Get(good.URL)
+	if err != nil {
+		t.Fatalf("Get good = %v, want nil (hosts fail independently)", err)
+	}
+	resp.Body.Close()
+}
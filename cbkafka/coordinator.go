@@ -0,0 +1,89 @@
+// Package cbkafka ties Kafka consumption to circuit breaker state: when
+// the breaker for a downstream the message handler depends on opens, the
+// consumer pauses partition fetching instead of dead-lettering or
+// dropping everything it polls, and resumes once the circuit lets traffic
+// through again. Offsets stay put, so the backlog is simply processed
+// later.
+//
+// The package depends only on a small PauseResumer interface rather than
+// a concrete Kafka library; sarama's ConsumerGroup satisfies it directly
+// (PauseAll/ResumeAll) and confluent-kafka-go's Consumer does via a thin
+// adapter over Pause/Resume with its assigned partitions.
+package cbkafka
+
+import (
+	"sync"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// PauseResumer is the minimal consumer surface the coordinator needs.
+// PauseAll must stop fetching from all assigned partitions without
+// leaving the group — pausing, not unsubscribing — and ResumeAll must
+// undo it. Both may be called more than once; the coordinator never
+// pauses twice without resuming in between.
+type PauseResumer interface {
+	PauseAll()
+	ResumeAll()
+}
+
+// Coordinator pauses a consumer while any watched downstream breaker is
+// open and resumes it when the last one leaves the open state. Resuming
+// on half-open is deliberate: probe traffic has to flow for the circuit
+// to close, and if the probes fail the breaker re-opens and the consumer
+// pauses again.
+type Coordinator struct {
+	mu       sync.Mutex
+	consumer PauseResumer
+	open     map[string]struct{}
+	paused   bool
+}
+
+// NewCoordinator returns a Coordinator controlling consumer. Wire it to
+// breakers with Watch, or use Observe directly as a Config.OnStateChange
+// hook.
+func NewCoordinator(consumer PauseResumer) *Coordinator {
+	return &Coordinator{consumer: consumer, open: make(map[string]struct{})}
+}
+
+// Watch pauses the consumer while any breaker created by registry is
+// open. Breakers already open when Watch is called are picked up too.
+func (c *Coordinator) Watch(registry *circuitbreaker.Registry) {
+	registry.OnStateChange(c.Observe)
+	for _, b := range registry.All() {
+		if b.State() == circuitbreaker.StateOpen {
+			c.Observe(b.Name(), circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+		}
+	}
+}
+
+// Observe feeds one state change into the coordinator. It is safe to
+// install as a breaker's Config.OnStateChange (state-change hooks run
+// outside the breaker's lock) and to share across breakers guarding
+// different downstreams — the consumer stays paused until every one of
+// them has left the open state.
+func (c *Coordinator) Observe(name string, from, to circuitbreaker.State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if to == circuitbreaker.StateOpen {
+		c.open[name] = struct{}{}
+	} else {
+		delete(c.open, name)
+	}
+	switch {
+	case len(c.open) > 0 && !c.paused:
+		c.paused = true
+		c.consumer.PauseAll()
+	case len(c.open) == 0 && c.paused:
+		c.paused = false
+		c.consumer.ResumeAll()
+	}
+}
+
+// Paused reports whether the coordinator currently holds the consumer
+// paused.
+func (c *Coordinator) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
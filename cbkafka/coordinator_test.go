@@ -0,0 +1,91 @@
+package cbkafka
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+type fakeConsumer struct {
+	mu      sync.Mutex
+	pauses  int
+	resumes int
+}
+
+func (c *fakeConsumer) PauseAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pauses++
+}
+
+func (c *fakeConsumer) ResumeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resumes++
+}
+
+func (c *fakeConsumer) counts() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pauses, c.resumes
+}
+
+func TestCoordinatorPausesWhileAnyBreakerOpen(t *testing.T) {
+	consumer := &fakeConsumer{}
+	c := NewCoordinator(consumer)
+
+	c.Observe("db", circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+	if !c.Paused() {
+		t.Fatal("not paused after a breaker opened")
+	}
+	c.Observe("cache", circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+	c.Observe("db", circuitbreaker.StateOpen, circuitbreaker.StateHalfOpen)
+	if !c.Paused() {
+		t.Fatal("resumed while another breaker is still open")
+	}
+	c.Observe("cache", circuitbreaker.StateOpen, circuitbreaker.StateClosed)
+	if c.Paused() {
+		t.Fatal("still paused after the last breaker recovered")
+	}
+
+	pauses, resumes := consumer.counts()
+	if pauses != 1 || resumes != 1 {
+		t.Fatalf("pauses=%d resumes=%d, want exactly one of each", pauses, resumes)
+	}
+}
+
+func TestCoordinatorResumesOnHalfOpen(t *testing.T) {
+	// Probe traffic has to flow for the circuit to close.
+	consumer := &fakeConsumer{}
+	c := NewCoordinator(consumer)
+	c.Observe("db", circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+	c.Observe("db", circuitbreaker.StateOpen, circuitbreaker.StateHalfOpen)
+	if c.Paused() {
+		t.Fatal("still paused in half-open; probes would never flow")
+	}
+}
+
+func TestCoordinatorWatchPicksUpRegistry(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	// Already-open breakers are adopted at Watch time.
+	registry.Get("db").ForceOpen()
+
+	consumer := &fakeConsumer{}
+	c := NewCoordinator(consumer)
+	c.Watch(registry)
+	if !c.Paused() {
+		t.Fatal("Watch did not adopt an already-open breaker")
+	}
+
+	registry.Get("db").Reset()
+	if c.Paused() {
+		t.Fatal("still paused after the watched breaker closed")
+	}
+}
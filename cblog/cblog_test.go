@@ -0,0 +1,90 @@
+package cblog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripRegistry() *circuitbreaker.Registry {
+	return circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+}
+
+func TestZapEventsLogsTripWithCounts(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	e := NewZapEvents(zap.New(core))
+	registry := tripRegistry()
+	e.Attach(registry)
+
+	registry.Get("db").Do(func() error { return errors.New("boom") })
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != zap.WarnLevel || entry.Message != "circuit breaker opened" {
+		t.Fatalf("entry = %v %q", entry.Level, entry.Message)
+	}
+	fields := entry.ContextMap()
+	if fields["breaker"] != "db" || fields["to"] != "open" {
+		t.Fatalf("fields = %v", fields)
+	}
+	if fields["consecutive_failures"] != uint64(1) {
+		t.Fatalf("consecutive_failures = %v, want 1", fields["consecutive_failures"])
+	}
+}
+
+func TestZapEventsLogsRecoveryAtInfo(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	e := NewZapEvents(zap.New(core))
+	registry := tripRegistry()
+	e.Attach(registry)
+
+	b := registry.Get("db")
+	b.Do(func() error { return errors.New("boom") })
+	b.Reset()
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	if entries[1].Level != zap.InfoLevel || entries[1].ContextMap()["to"] != "closed" {
+		t.Fatalf("recovery entry = %v %v", entries[1].Level, entries[1].ContextMap())
+	}
+}
+
+func TestLogrusEventsLogsTripWithCounts(t *testing.T) {
+	logger, hook := logrustest.NewNullLogger()
+	e := NewLogrusEvents(logger)
+	registry := tripRegistry()
+	e.Attach(registry)
+
+	registry.Get("db").Do(func() error { return errors.New("boom") })
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("nothing logged")
+	}
+	if entry.Level != logrus.WarnLevel || entry.Message != "circuit breaker opened" {
+		t.Fatalf("entry = %v %q", entry.Level, entry.Message)
+	}
+	if entry.Data["breaker"] != "db" || entry.Data["to"] != "open" {
+		t.Fatalf("fields = %v", entry.Data)
+	}
+	if entry.Data["consecutive_failures"] != uint64(1) {
+		t.Fatalf("consecutive_failures = %v, want 1", entry.Data["consecutive_failures"])
+	}
+}
@@ -0,0 +1,66 @@
+package cblog
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// LogrusEvents logs breaker events with logrus; see ZapEvents for usage.
+type LogrusEvents struct {
+	logger logrus.FieldLogger
+}
+
+// NewLogrusEvents returns an event logger writing to logger.
+func NewLogrusEvents(logger logrus.FieldLogger) *LogrusEvents {
+	return &LogrusEvents{logger: logger}
+}
+
+// Attach registers the event logger for all state changes on r. Trips are
+// logged with the counts that caused them.
+func (e *LogrusEvents) Attach(r *circuitbreaker.Registry) {
+	r.OnStateChange(func(name string, from, to circuitbreaker.State) {
+		fields := logrus.Fields{
+			"breaker": name,
+			"from":    from.String(),
+			"to":      to.String(),
+		}
+		if to == circuitbreaker.StateOpen {
+			if b, ok := r.Lookup(name); ok {
+				counts := b.TripCounts()
+				fields["requests"] = counts.Requests
+				fields["failures"] = counts.TotalFailures
+				fields["consecutive_failures"] = counts.ConsecutiveFailures
+			}
+			e.logger.WithFields(fields).Warn("circuit breaker opened")
+			return
+		}
+		e.logger.WithFields(fields).Info("circuit breaker state changed")
+	})
+}
+
+// RecordSuccess implements circuitbreaker.MetricsCollector; successes are
+// not logged.
+func (e *LogrusEvents) RecordSuccess(string, time.Duration) {}
+
+// RecordFailure implements circuitbreaker.MetricsCollector; individual
+// failures are not logged — the trip that results from them is.
+func (e *LogrusEvents) RecordFailure(string, time.Duration) {}
+
+// RecordRejection implements circuitbreaker.MetricsCollector.
+func (e *LogrusEvents) RecordRejection(name string) {
+	e.logger.WithField("breaker", name).Debug("circuit breaker rejected request")
+}
+
+// RecordStateChange implements circuitbreaker.MetricsCollector, for
+// breakers that are not managed by a registry. Use either Attach or the
+// Metrics hook for a given breaker, not both.
+func (e *LogrusEvents) RecordStateChange(name string, from, to circuitbreaker.State) {
+	e.logger.WithFields(logrus.Fields{
+		"breaker": name,
+		"from":    from.String(),
+		"to":      to.String(),
+	}).Info("circuit breaker state changed")
+}
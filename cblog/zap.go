@@ -0,0 +1,73 @@
+// Package cblog provides breaker event loggers for zap and logrus,
+// mirroring circuitbreaker.SlogEvents, so services standardized on either
+// library get structured breaker logs without bridge boilerplate.
+package cblog
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ZapEvents logs breaker events with zap. Attach it to a registry for
+// registry-wide logging, or use it as a breaker's Metrics collector
+// (combined with a real backend via circuitbreaker.MultiCollector).
+type ZapEvents struct {
+	logger *zap.Logger
+}
+
+// NewZapEvents returns an event logger writing to logger.
+func NewZapEvents(logger *zap.Logger) *ZapEvents {
+	return &ZapEvents{logger: logger}
+}
+
+// Attach registers the event logger for all state changes on r. Trips are
+// logged with the counts that caused them.
+func (e *ZapEvents) Attach(r *circuitbreaker.Registry) {
+	r.OnStateChange(func(name string, from, to circuitbreaker.State) {
+		fields := []zap.Field{
+			zap.String("breaker", name),
+			zap.String("from", from.String()),
+			zap.String("to", to.String()),
+		}
+		if to == circuitbreaker.StateOpen {
+			if b, ok := r.Lookup(name); ok {
+				counts := b.TripCounts()
+				fields = append(fields,
+					zap.Uint64("requests", counts.Requests),
+					zap.Uint64("failures", counts.TotalFailures),
+					zap.Uint64("consecutive_failures", counts.ConsecutiveFailures),
+				)
+			}
+			e.logger.Warn("circuit breaker opened", fields...)
+			return
+		}
+		e.logger.Info("circuit breaker state changed", fields...)
+	})
+}
+
+// RecordSuccess implements circuitbreaker.MetricsCollector; successes are
+// not logged.
+func (e *ZapEvents) RecordSuccess(string, time.Duration) {}
+
+// RecordFailure implements circuitbreaker.MetricsCollector; individual
+// failures are not logged — the trip that results from them is.
+func (e *ZapEvents) RecordFailure(string, time.Duration) {}
+
+// RecordRejection implements circuitbreaker.MetricsCollector.
+func (e *ZapEvents) RecordRejection(name string) {
+	e.logger.Debug("circuit breaker rejected request", zap.String("breaker", name))
+}
+
+// RecordStateChange implements circuitbreaker.MetricsCollector, for
+// breakers that are not managed by a registry. Use either Attach or the
+// Metrics hook for a given breaker, not both.
+func (e *ZapEvents) RecordStateChange(name string, from, to circuitbreaker.State) {
+	e.logger.Info("circuit breaker state changed",
+		zap.String("breaker", name),
+		zap.String("from", from.String()),
+		zap.String("to", to.String()),
+	)
+}
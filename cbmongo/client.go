@@ -0,0 +1,143 @@
+package cbmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Client wraps a *mongo.Client so new operations fail fast with
+// circuitbreaker.ErrOpen while the cluster's circuit is open. Outcomes
+// are recorded by the Monitor, not here, so the wrapper only gates
+// admission; operations the wrapper doesn't cover can still be reached
+// through Client(), bypassing the gate but not the recording.
+type Client struct {
+	client  *mongo.Client
+	breaker *circuitbreaker.Breaker
+}
+
+// WrapClient returns client gated by breaker — the same breaker the
+// Monitor installed by Configure records into.
+func WrapClient(client *mongo.Client, breaker *circuitbreaker.Breaker) *Client {
+	return &Client{client: client, breaker: breaker}
+}
+
+// Client returns the underlying client.
+func (c *Client) Client() *mongo.Client { return c.client }
+
+// Ping is gated like any other operation.
+func (c *Client) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	if err := admit(c.breaker); err != nil {
+		return err
+	}
+	return c.client.Ping(ctx, rp)
+}
+
+// Collection returns a gated handle on the named collection.
+func (c *Client) Collection(database, name string, opts ...*options.CollectionOptions) *Collection {
+	return &Collection{
+		coll:    c.client.Database(database).Collection(name, opts...),
+		breaker: c.breaker,
+	}
+}
+
+// Collection gates the common CRUD operations on a mongo collection.
+type Collection struct {
+	coll    *mongo.Collection
+	breaker *circuitbreaker.Breaker
+}
+
+// Collection returns the underlying collection.
+func (c *Collection) Collection() *mongo.Collection { return c.coll }
+
+func (c *Collection) Find(ctx context.Context, filter any, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.Find(ctx, filter, opts...)
+}
+
+func (c *Collection) FindOne(ctx context.Context, filter any, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if err := admit(c.breaker); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return c.coll.FindOne(ctx, filter, opts...)
+}
+
+func (c *Collection) InsertOne(ctx context.Context, document any, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.InsertOne(ctx, document, opts...)
+}
+
+func (c *Collection) InsertMany(ctx context.Context, documents []any, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.InsertMany(ctx, documents, opts...)
+}
+
+func (c *Collection) UpdateOne(ctx context.Context, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.UpdateOne(ctx, filter, update, opts...)
+}
+
+func (c *Collection) UpdateMany(ctx context.Context, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.UpdateMany(ctx, filter, update, opts...)
+}
+
+func (c *Collection) ReplaceOne(ctx context.Context, filter, replacement any, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.ReplaceOne(ctx, filter, replacement, opts...)
+}
+
+func (c *Collection) DeleteOne(ctx context.Context, filter any, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.DeleteOne(ctx, filter, opts...)
+}
+
+func (c *Collection) DeleteMany(ctx context.Context, filter any, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.DeleteMany(ctx, filter, opts...)
+}
+
+func (c *Collection) Aggregate(ctx context.Context, pipeline any, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if err := admit(c.breaker); err != nil {
+		return nil, err
+	}
+	return c.coll.Aggregate(ctx, pipeline, opts...)
+}
+
+func (c *Collection) CountDocuments(ctx context.Context, filter any, opts ...*options.CountOptions) (int64, error) {
+	if err := admit(c.breaker); err != nil {
+		return 0, err
+	}
+	return c.coll.CountDocuments(ctx, filter, opts...)
+}
+
+// admit rejects while the circuit is open; outcome recording happens in
+// the Monitor, mirroring cbpgx's split between pool hooks and tracer.
+func admit(b *circuitbreaker.Breaker) error {
+	if b.State() == circuitbreaker.StateOpen {
+		b.ReportRejection(circuitbreaker.ReasonOpen)
+		return circuitbreaker.ErrOpen
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Package cbmongo integrates circuit breakers with the official MongoDB
+// driver. Command outcomes and latencies feed a per-cluster breaker
+// through an event.CommandMonitor, and the client wrapper rejects new
+// operations while the circuit is open — failing in microseconds instead
+// of burning the full server-selection timeout against a cluster the
+// breaker already knows is down.
+package cbmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ErrSlowCommand is the failure recorded against the breaker when a
+// command succeeded but exceeded the slow-call threshold.
+var ErrSlowCommand = errors.New("cbmongo: slow command")
+
+// monitorOptions carries the integration's configuration.
+type monitorOptions struct {
+	slowThreshold time.Duration
+}
+
+// Option configures the integration.
+type Option func(*monitorOptions)
+
+// WithSlowCallThreshold counts commands slower than d as failures —
+// recorded as ErrSlowCommand — even when they succeed.
+func WithSlowCallThreshold(d time.Duration) Option {
+	return func(o *monitorOptions) { o.slowThreshold = d }
+}
+
+// Monitor feeds command outcomes into a breaker. Like cbpgx's tracer it
+// records post-hoc — a monitor cannot abort a command, admission control
+// lives in the client wrapper — so a command running while the breaker
+// is open simply drops its outcome. Heartbeat commands ("hello" and its
+// legacy spellings) are ignored: they run on monitoring connections every
+// few seconds and would otherwise dilute the application's failure rate.
+type Monitor struct {
+	breaker  *circuitbreaker.Breaker
+	opts     monitorOptions
+	mu       sync.Mutex
+	inflight map[int64]func(error)
+}
+
+// NewMonitor returns a Monitor recording into breaker.
+func NewMonitor(breaker *circuitbreaker.Breaker, opts ...Option) *Monitor {
+	o := monitorOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Monitor{breaker: breaker, opts: o, inflight: make(map[int64]func(error))}
+}
+
+// Configure installs the integration into client options: the command
+// monitor records outcomes (chained with any monitor already set), and
+// WrapClient on the connected client adds the fail-fast side.
+func Configure(co *options.ClientOptions, breaker *circuitbreaker.Breaker, opts ...Option) *Monitor {
+	m := NewMonitor(breaker, opts...)
+	co.SetMonitor(m.CommandMonitor(co.Monitor))
+	return m
+}
+
+// CommandMonitor returns the event.CommandMonitor view of m, forwarding
+// every event to next when it is non-nil.
+func (m *Monitor) CommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if !heartbeat(evt.CommandName) {
+				m.started(evt.RequestID)
+			}
+			if next != nil && next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			m.finish(evt.RequestID, m.classifySuccess(evt.Duration))
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			m.finish(evt.RequestID, m.classifyFailure(ctx, evt))
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+func (m *Monitor) started(requestID int64) {
+	done, err := m.breaker.Allow()
+	if err != nil {
+		return
+	}
+	m.mu.Lock()
+	m.inflight[requestID] = done
+	m.mu.Unlock()
+}
+
+func (m *Monitor) finish(requestID int64, outcome error) {
+	m.mu.Lock()
+	done, ok := m.inflight[requestID]
+	if ok {
+		delete(m.inflight, requestID)
+	}
+	m.mu.Unlock()
+	if ok {
+		done(outcome)
+	}
+}
+
+func (m *Monitor) classifySuccess(duration time.Duration) error {
+	if m.opts.slowThreshold > 0 && duration > m.opts.slowThreshold {
+		return ErrSlowCommand
+	}
+	return nil
+}
+
+func (m *Monitor) classifyFailure(ctx context.Context, evt *event.CommandFailedEvent) error {
+	// The caller giving up says nothing about the cluster's health.
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	return fmt.Errorf("cbmongo: %s failed: %s", evt.CommandName, evt.Failure)
+}
+
+// heartbeat reports whether name is a server-monitoring command.
+func heartbeat(name string) bool {
+	switch name {
+	case "hello", "isMaster", "ismaster":
+		return true
+	}
+	return false
+}
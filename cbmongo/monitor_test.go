@@ -0,0 +1,122 @@
+package cbmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+// run drives one command through the monitor as the driver would.
+func run(cm *event.CommandMonitor, name string, requestID int64, fail bool) {
+	ctx := context.Background()
+	cm.Started(ctx, &event.CommandStartedEvent{CommandName: name, RequestID: requestID})
+	finished := event.CommandFinishedEvent{CommandName: name, RequestID: requestID}
+	if fail {
+		cm.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: "network error"})
+	} else {
+		cm.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished})
+	}
+}
+
+func TestMonitorRecordsOutcomes(t *testing.T) {
+	breaker := tripAfter(2)
+	cm := NewMonitor(breaker).CommandMonitor(nil)
+
+	run(cm, "find", 1, false)
+	run(cm, "find", 2, true)
+	run(cm, "find", 3, true)
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after two failed commands", got)
+	}
+	// The open transition starts a fresh generation, so inspect the
+	// counts captured at the moment the breaker tripped.
+	if got := breaker.TripCounts(); got.TotalSuccesses != 1 || got.TotalFailures != 2 {
+		t.Fatalf("trip counts = %+v, want 1 success / 2 failures", got)
+	}
+}
+
+func TestMonitorIgnoresHeartbeats(t *testing.T) {
+	breaker := tripAfter(1)
+	cm := NewMonitor(breaker).CommandMonitor(nil)
+
+	run(cm, "hello", 1, true)
+	run(cm, "isMaster", 2, true)
+	if got := breaker.Counts(); got.Requests != 0 {
+		t.Fatalf("Requests = %d, want 0 (heartbeats are not application traffic)", got.Requests)
+	}
+}
+
+func TestMonitorDropsOutcomesWhileOpen(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	cm := NewMonitor(breaker).CommandMonitor(nil)
+
+	// A monitor cannot abort a command; while the circuit is open it
+	// simply records nothing, and the late finish finds no inflight entry.
+	run(cm, "find", 1, true)
+	if got := breaker.Counts(); got.Requests != 0 {
+		t.Fatalf("Requests = %d, want 0", got.Requests)
+	}
+}
+
+func TestMonitorSlowCommandCountsAsFailure(t *testing.T) {
+	breaker := tripAfter(1)
+	cm := NewMonitor(breaker, WithSlowCallThreshold(time.Millisecond)).CommandMonitor(nil)
+
+	ctx := context.Background()
+	cm.Started(ctx, &event.CommandStartedEvent{CommandName: "find", RequestID: 1})
+	cm.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "find", RequestID: 1, Duration: 10 * time.Millisecond,
+	}})
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after slow command", got)
+	}
+	samples := breaker.Status().RecentErrors
+	if len(samples) == 0 || samples[0].Error != ErrSlowCommand.Error() {
+		t.Fatalf("samples = %+v, want ErrSlowCommand", samples)
+	}
+}
+
+func TestMonitorCanceledCommandIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	cm := NewMonitor(breaker).CommandMonitor(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cm.Started(ctx, &event.CommandStartedEvent{CommandName: "find", RequestID: 1})
+	cancel()
+	cm.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1},
+		Failure:              "operation was interrupted",
+	})
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (cancellation is neutral)", got)
+	}
+}
+
+func TestMonitorChainsNext(t *testing.T) {
+	breaker := tripAfter(1)
+	var started, succeeded int
+	next := &event.CommandMonitor{
+		Started:   func(context.Context, *event.CommandStartedEvent) { started++ },
+		Succeeded: func(context.Context, *event.CommandSucceededEvent) { succeeded++ },
+	}
+	cm := NewMonitor(breaker).CommandMonitor(next)
+
+	run(cm, "find", 1, false)
+	if started != 1 || succeeded != 1 {
+		t.Fatalf("next saw started=%d succeeded=%d, want 1/1", started, succeeded)
+	}
+}
@@ -0,0 +1,53 @@
+// Package cbmux adapts the circuit breaker middleware to gorilla/mux.
+// Like chi, mux middleware is plain net/http, so the package provides a
+// route-key extractor reading the matched route's path template and
+// helpers wiring it into cbhttp.HTTPMiddleware, per router or per
+// subrouter.
+package cbmux
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbhttp"
+)
+
+// RouteKey returns the request's breaker key as method plus the matched
+// route's path template, e.g. "GET /v1/users/{id}". Routes without a path
+// template (host- or header-matched) fall back to the route's name, and
+// "unmatched" covers everything else. Mux runs middleware after matching,
+// so the template is available whether the middleware is attached with
+// Router.Use on the router or on a subrouter.
+func RouteKey(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil && template != "" {
+			return r.Method + " " + template
+		}
+		if name := route.GetName(); name != "" {
+			return r.Method + " " + name
+		}
+	}
+	return r.Method + " unmatched"
+}
+
+// Middleware returns a mux.MiddlewareFunc with a breaker per route
+// template, fetched from registry and created on demand with its
+// defaults. opts configure the underlying cbhttp.HTTPMiddleware
+// (fallbacks, bulkhead, observer, ...); a cbhttp.WithRouteBreakers among
+// them overrides the route keying.
+func Middleware(registry *circuitbreaker.Registry, opts ...cbhttp.MiddlewareOption) mux.MiddlewareFunc {
+	opts = append([]cbhttp.MiddlewareOption{cbhttp.WithRouteBreakers(registry, RouteKey)}, opts...)
+	return cbhttp.NewMiddleware(nil, opts...).Wrap
+}
+
+// Use attaches the middleware to router. Called on subrouters it scopes
+// the configuration to that subtree, so e.g. the admin subrouter can run
+// with a different registry and fallback than the public API:
+//
+//	cbmux.Use(router.PathPrefix("/api").Subrouter(), apiRegistry, apiOpts...)
+//	cbmux.Use(router.PathPrefix("/admin").Subrouter(), adminRegistry)
+func Use(router *mux.Router, registry *circuitbreaker.Registry, opts ...cbhttp.MiddlewareOption) {
+	router.Use(Middleware(registry, opts...))
+}
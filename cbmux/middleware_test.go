@@ -0,0 +1,89 @@
+package cbmux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func newRegistry() *circuitbreaker.Registry {
+	return circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestMiddlewareKeysByPathTemplate(t *testing.T) {
+	registry := newRegistry()
+	router := mux.NewRouter()
+	Use(router, registry)
+	router.HandleFunc("/bad/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}).Methods(http.MethodGet)
+	router.HandleFunc("/good", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+	get(t, router, "/bad/1")
+
+	// The breaker keys on the path template, so a different id shares the
+	// open breaker and the key set stays bounded.
+	if _, ok := registry.Lookup("GET /bad/{id}"); !ok {
+		t.Fatal("no breaker registered under the path template")
+	}
+	if rec := get(t, router, "/bad/2"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/bad/2 status = %d, want 503", rec.Code)
+	}
+	if rec := get(t, router, "/good"); rec.Code != http.StatusOK {
+		t.Fatalf("/good status = %d, want 200", rec.Code)
+	}
+}
+
+func TestUseScopesToSubrouter(t *testing.T) {
+	apiRegistry := newRegistry()
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	Use(api, apiRegistry)
+	api.HandleFunc("/work", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}).Methods(http.MethodGet)
+	router.HandleFunc("/public", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+	get(t, router, "/api/work")
+	if rec := get(t, router, "/api/work"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/api/work status = %d, want 503", rec.Code)
+	}
+	// Routes outside the subrouter never touch the registry.
+	if rec := get(t, router, "/public"); rec.Code != http.StatusOK {
+		t.Fatalf("/public status = %d, want 200", rec.Code)
+	}
+	if got := len(apiRegistry.All()); got != 1 {
+		t.Fatalf("registry has %d breakers, want 1 (subtree only)", got)
+	}
+}
+
+func TestRouteKeyFallsBackToName(t *testing.T) {
+	router := mux.NewRouter()
+	var key string
+	router.Host("example.com").Name("by-host").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = RouteKey(r)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/x", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if key != "GET by-host" {
+		t.Fatalf("RouteKey = %q, want \"GET by-host\"", key)
+	}
+}
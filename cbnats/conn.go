@@ -0,0 +1,206 @@
+// Package cbnats wraps a nats.go connection so publishes and requests run
+// through a circuit breaker. Requests that time out — or succeed too
+// slowly — count as failures, and while the circuit is open publishes can
+// be rejected, dropped, or buffered in a bounded queue and replayed once
+// the subject is healthy again.
+package cbnats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ErrSlowCall is the failure recorded against the breaker when a request
+// got its reply but took longer than the slow-call threshold.
+var ErrSlowCall = errors.New("cbnats: slow request")
+
+// ErrQueueFull is returned by Publish when the circuit is open and the
+// replay queue configured by WithQueueWhileOpen has no room left.
+var ErrQueueFull = errors.New("cbnats: open-state publish queue is full")
+
+// Conn wraps a *nats.Conn with breaker admission. Construct it with Wrap;
+// the zero value is not usable.
+type Conn struct {
+	nc            *nats.Conn
+	breaker       *circuitbreaker.Breaker
+	registry      *circuitbreaker.Registry
+	slowThreshold time.Duration
+	dropWhileOpen bool
+	queue         chan *nats.Msg
+}
+
+// Option configures the wrapper.
+type Option func(*Conn)
+
+// WithSubjectBreakers keys admission by subject instead of one breaker
+// for the whole connection, so a dead responder on one subject doesn't
+// shed traffic to the others. Breakers come from registry, created on
+// demand with its defaults.
+func WithSubjectBreakers(registry *circuitbreaker.Registry) Option {
+	return func(c *Conn) { c.registry = registry }
+}
+
+// WithSlowCallThreshold counts requests slower than d as failures —
+// recorded as ErrSlowCall — even when a reply arrives, so a responder
+// that is up but drowning still trips the breaker.
+func WithSlowCallThreshold(d time.Duration) Option {
+	return func(c *Conn) { c.slowThreshold = d }
+}
+
+// WithDropWhileOpen makes Publish silently drop messages shed by an open
+// circuit instead of returning an error — for fire-and-forget telemetry
+// where losing events beats failing the caller.
+func WithDropWhileOpen() Option {
+	return func(c *Conn) { c.dropWhileOpen = true }
+}
+
+// WithQueueWhileOpen buffers up to n messages shed by an open circuit and
+// replays them, oldest first, once publishes are being admitted again;
+// Publish returns ErrQueueFull when the buffer is exhausted. Replay is
+// best-effort and in-memory — messages still queued when the process
+// exits are lost.
+func WithQueueWhileOpen(n int) Option {
+	return func(c *Conn) { c.queue = make(chan *nats.Msg, n) }
+}
+
+// Wrap returns a Conn guarding nc with breaker. The breaker may be nil
+// if WithSubjectBreakers is used.
+func Wrap(nc *nats.Conn, breaker *circuitbreaker.Breaker, opts ...Option) *Conn {
+	c := &Conn{nc: nc, breaker: breaker}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Conn returns the underlying connection, for operations the wrapper does
+// not cover (subscriptions, draining, JetStream).
+func (c *Conn) Conn() *nats.Conn { return c.nc }
+
+// Publish publishes data to subject through the breaker.
+func (c *Conn) Publish(subject string, data []byte) error {
+	return c.PublishMsg(&nats.Msg{Subject: subject, Data: data})
+}
+
+// PublishMsg publishes msg through the breaker. While the circuit for
+// msg's subject is open the message is dropped or queued per the
+// configured option, or the breaker error is returned.
+func (c *Conn) PublishMsg(msg *nats.Msg) error {
+	b := c.breakerFor(msg.Subject)
+	done, err := b.Allow()
+	if err != nil {
+		return c.shed(msg, err)
+	}
+	pubErr := c.nc.PublishMsg(msg)
+	done(pubErr)
+	if pubErr == nil {
+		c.replay()
+	}
+	return pubErr
+}
+
+// Request sends a request through the breaker and waits up to timeout for
+// the reply. Timeouts, no-responder errors and replies slower than the
+// slow-call threshold all count as failures.
+func (c *Conn) Request(subject string, data []byte, timeout time.Duration) (*nats.Msg, error) {
+	b := c.breakerFor(subject)
+	done, err := b.Allow()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	reply, reqErr := c.nc.Request(subject, data, timeout)
+	done(c.classify(reqErr, start))
+	return reply, reqErr
+}
+
+// RequestWithContext is Request with context-based cancellation; caller
+// cancellation is recorded neutrally, as everywhere else in this module.
+func (c *Conn) RequestWithContext(ctx context.Context, subject string, data []byte) (*nats.Msg, error) {
+	b := c.breakerFor(subject)
+	done, err := b.AllowContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	reply, reqErr := c.nc.RequestWithContext(ctx, subject, data)
+	if errors.Is(reqErr, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+		done(nil)
+	} else {
+		done(c.classify(reqErr, start))
+	}
+	return reply, reqErr
+}
+
+// Queued reports how many shed publishes are waiting for replay.
+func (c *Conn) Queued() int {
+	if c.queue == nil {
+		return 0
+	}
+	return len(c.queue)
+}
+
+func (c *Conn) breakerFor(subject string) *circuitbreaker.Breaker {
+	if c.registry != nil {
+		return c.registry.Get(subject)
+	}
+	return c.breaker
+}
+
+func (c *Conn) classify(err error, start time.Time) error {
+	switch {
+	case err != nil:
+		return err
+	case c.slowThreshold > 0 && time.Since(start) > c.slowThreshold:
+		return ErrSlowCall
+	}
+	return nil
+}
+
+// shed handles a publish rejected by the breaker.
+func (c *Conn) shed(msg *nats.Msg, err error) error {
+	if c.queue != nil {
+		select {
+		case c.queue <- msg:
+			return nil
+		default:
+			return ErrQueueFull
+		}
+	}
+	if c.dropWhileOpen {
+		return nil
+	}
+	return err
+}
+
+// replay drains queued publishes after a successful publish proved the
+// path healthy, stopping at the first rejection or error; whatever
+// remains waits for the next successful publish.
+func (c *Conn) replay() {
+	if c.queue == nil {
+		return
+	}
+	for {
+		select {
+		case msg := <-c.queue:
+			b := c.breakerFor(msg.Subject)
+			done, err := b.Allow()
+			if err != nil {
+				c.shed(msg, err)
+				return
+			}
+			pubErr := c.nc.PublishMsg(msg)
+			done(pubErr)
+			if pubErr != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package cbnats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// The shed and classification paths run entirely before the wrapped
+// *nats.Conn is touched, so they are tested here without a server; the
+// publish and request happy paths are exercised by integration
+// environments with a real NATS.
+
+func openBreaker() *circuitbreaker.Breaker {
+	b := circuitbreaker.New(circuitbreaker.Config{OpenTimeout: time.Minute})
+	b.ForceOpen()
+	return b
+}
+
+func TestPublishWhileOpenReturnsBreakerError(t *testing.T) {
+	c := Wrap(nil, openBreaker())
+	if err := c.Publish("orders.created", nil); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Publish = %v, want ErrOpen", err)
+	}
+}
+
+func TestPublishWhileOpenDrops(t *testing.T) {
+	c := Wrap(nil, openBreaker(), WithDropWhileOpen())
+	if err := c.Publish("orders.created", nil); err != nil {
+		t.Fatalf("Publish = %v, want nil (dropped)", err)
+	}
+}
+
+func TestPublishWhileOpenQueuesUntilFull(t *testing.T) {
+	c := Wrap(nil, openBreaker(), WithQueueWhileOpen(2))
+	for i := 0; i < 2; i++ {
+		if err := c.Publish("orders.created", nil); err != nil {
+			t.Fatalf("Publish %d = %v, want nil (queued)", i, err)
+		}
+	}
+	if got := c.Queued(); got != 2 {
+		t.Fatalf("Queued = %d, want 2", got)
+	}
+	if err := c.Publish("orders.created", nil); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Publish past capacity = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestRequestFailsFastWhileOpen(t *testing.T) {
+	c := Wrap(nil, openBreaker())
+	if _, err := c.Request("orders.lookup", nil, time.Second); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Request = %v, want ErrOpen", err)
+	}
+}
+
+func TestSubjectBreakersShedIndependently(t *testing.T) {
+	registry := circuitbreaker.NewRegistry(circuitbreaker.Config{OpenTimeout: time.Minute})
+	registry.Get("orders.created").ForceOpen()
+	c := Wrap(nil, nil, WithSubjectBreakers(registry))
+
+	if err := c.Publish("orders.created", nil); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("open subject = %v, want ErrOpen", err)
+	}
+	if got := registry.Get("orders.deleted").State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("sibling subject state = %v, want closed", got)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	c := Wrap(nil, nil, WithSlowCallThreshold(10*time.Millisecond))
+	boom := errors.New("no responders")
+
+	if got := c.classify(boom, time.Now()); !errors.Is(got, boom) {
+		t.Fatalf("classify(err) = %v, want the error", got)
+	}
+	if got := c.classify(nil, time.Now()); got != nil {
+		t.Fatalf("classify(fast) = %v, want nil", got)
+	}
+	if got := c.classify(nil, time.Now().Add(-time.Second)); !errors.Is(got, ErrSlowCall) {
+		t.Fatalf("classify(slow) = %v, want ErrSlowCall", got)
+	}
+}
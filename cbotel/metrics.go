@@ -0,0 +1,126 @@
+// Package cbotel exports circuit breaker telemetry through OpenTelemetry,
+// for services standardized on OTLP export rather than Prometheus scrapes.
+package cbotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Metrics is a circuitbreaker.MetricsCollector emitting OTel instruments:
+//
+//	circuitbreaker.requests        (counter, attrs: name, outcome)
+//	circuitbreaker.rejections      (counter, attrs: name)
+//	circuitbreaker.state_changes   (counter, attrs: name, from, to)
+//	circuitbreaker.open_breakers   (up/down counter, attrs: name)
+//	circuitbreaker.request_duration (histogram, seconds, attrs: name, outcome)
+type Metrics struct {
+	requests     metric.Int64Counter
+	rejections   metric.Int64Counter
+	stateChanges metric.Int64Counter
+	openBreakers metric.Int64UpDownCounter
+	duration     metric.Float64Histogram
+
+	mu    sync.RWMutex
+	attrs map[string][]attribute.KeyValue // per-breaker resource attributes
+}
+
+// NewMetrics creates the instruments on meter.
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	m := Metrics{attrs: make(map[string][]attribute.KeyValue)}
+	var err error
+	if m.requests, err = meter.Int64Counter("circuitbreaker.requests",
+		metric.WithDescription("Requests attempted through the circuit breaker, by outcome."),
+	); err != nil {
+		return nil, err
+	}
+	if m.rejections, err = meter.Int64Counter("circuitbreaker.rejections",
+		metric.WithDescription("Requests rejected by the circuit breaker without being attempted."),
+	); err != nil {
+		return nil, err
+	}
+	if m.stateChanges, err = meter.Int64Counter("circuitbreaker.state_changes",
+		metric.WithDescription("Circuit breaker state transitions."),
+	); err != nil {
+		return nil, err
+	}
+	if m.openBreakers, err = meter.Int64UpDownCounter("circuitbreaker.open_breakers",
+		metric.WithDescription("Number of circuit breakers currently open."),
+	); err != nil {
+		return nil, err
+	}
+	if m.duration, err = meter.Float64Histogram("circuitbreaker.request_duration",
+		metric.WithDescription("Duration of requests attempted through the circuit breaker."),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// RecordSuccess implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordSuccess(name string, duration time.Duration) {
+	m.record(name, "success", duration)
+}
+
+// RecordFailure implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordFailure(name string, duration time.Duration) {
+	m.record(name, "failure", duration)
+}
+
+func (m *Metrics) record(name, outcome string, duration time.Duration) {
+	ctx := context.Background()
+	attrs := m.withAttributes(name, attribute.String("outcome", outcome))
+	m.requests.Add(ctx, 1, attrs)
+	m.duration.Record(ctx, duration.Seconds(), attrs)
+}
+
+// RecordRejection implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordRejection(name string) {
+	m.rejections.Add(context.Background(), 1, m.withAttributes(name))
+}
+
+// RecordStateChange implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordStateChange(name string, from, to circuitbreaker.State) {
+	ctx := context.Background()
+	m.stateChanges.Add(ctx, 1, m.withAttributes(name,
+		attribute.String("from", from.String()),
+		attribute.String("to", to.String()),
+	))
+	nameAttr := m.withAttributes(name)
+	if to == circuitbreaker.StateOpen {
+		m.openBreakers.Add(ctx, 1, nameAttr)
+	}
+	if from == circuitbreaker.StateOpen {
+		m.openBreakers.Add(ctx, -1, nameAttr)
+	}
+}
+
+// RecordAttributes implements circuitbreaker.AttributeRecorder: the
+// breaker's resource attributes (team, tier, ...) are attached to every
+// measurement recorded for it from then on.
+func (m *Metrics) RecordAttributes(name string, attrs map[string]string) {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	m.mu.Lock()
+	m.attrs[name] = kvs
+	m.mu.Unlock()
+}
+
+// withAttributes builds the measurement option for a breaker: its name,
+// the given extras and any registered resource attributes.
+func (m *Metrics) withAttributes(name string, extra ...attribute.KeyValue) metric.MeasurementOption {
+	kvs := append([]attribute.KeyValue{attribute.String("name", name)}, extra...)
+	m.mu.RLock()
+	kvs = append(kvs, m.attrs[name]...)
+	m.mu.RUnlock()
+	return metric.WithAttributes(kvs...)
+}
@@ -0,0 +1,134 @@
+package cbotel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// recorder collects measurements from the fake instruments; the repo has
+// no dependency on the OTel SDK, so the API's noop types are extended
+// just enough to observe what Metrics emits.
+type recorder struct {
+	mu      sync.Mutex
+	samples []sample
+}
+
+type sample struct {
+	instrument string
+	value      float64
+	attrs      attribute.Set
+}
+
+func (r *recorder) add(instrument string, value float64, opts []metric.AddOption) {
+	cfg := metric.NewAddConfig(opts)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, sample{instrument: instrument, value: value, attrs: cfg.Attributes()})
+}
+
+// sum totals the samples for instrument whose attribute sets contain all
+// of want.
+func (r *recorder) sum(instrument string, want ...attribute.KeyValue) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total float64
+next:
+	for _, s := range r.samples {
+		if s.instrument != instrument {
+			continue
+		}
+		for _, kv := range want {
+			if got, ok := s.attrs.Value(kv.Key); !ok || got.Emit() != kv.Value.Emit() {
+				continue next
+			}
+		}
+		total += s.value
+	}
+	return total
+}
+
+type fakeMeter struct {
+	noop.Meter
+	rec *recorder
+}
+
+type fakeCounter struct {
+	noop.Int64Counter
+	name string
+	rec  *recorder
+}
+
+func (c *fakeCounter) Add(_ context.Context, v int64, opts ...metric.AddOption) {
+	c.rec.add(c.name, float64(v), opts)
+}
+
+type fakeUpDown struct {
+	noop.Int64UpDownCounter
+	name string
+	rec  *recorder
+}
+
+func (c *fakeUpDown) Add(_ context.Context, v int64, opts ...metric.AddOption) {
+	c.rec.add(c.name, float64(v), opts)
+}
+
+func (m *fakeMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &fakeCounter{name: name, rec: m.rec}, nil
+}
+
+func (m *fakeMeter) Int64UpDownCounter(name string, _ ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return &fakeUpDown{name: name, rec: m.rec}, nil
+}
+
+func TestMetricsEmitsMeasurements(t *testing.T) {
+	rec := &recorder{}
+	m, err := NewMetrics(&fakeMeter{rec: rec})
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+
+	m.RecordSuccess("db", 5*time.Millisecond)
+	m.RecordFailure("db", 10*time.Millisecond)
+	m.RecordRejection("db")
+	m.RecordStateChange("db", circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+	m.RecordStateChange("db", circuitbreaker.StateOpen, circuitbreaker.StateHalfOpen)
+
+	if got := rec.sum("circuitbreaker.requests", attribute.String("name", "db"), attribute.String("outcome", "success")); got != 1 {
+		t.Fatalf("requests{outcome=success} = %v, want 1", got)
+	}
+	if got := rec.sum("circuitbreaker.requests", attribute.String("outcome", "failure")); got != 1 {
+		t.Fatalf("requests{outcome=failure} = %v, want 1", got)
+	}
+	if got := rec.sum("circuitbreaker.rejections", attribute.String("name", "db")); got != 1 {
+		t.Fatalf("rejections = %v, want 1", got)
+	}
+	if got := rec.sum("circuitbreaker.state_changes", attribute.String("from", "closed"), attribute.String("to", "open")); got != 1 {
+		t.Fatalf("state_changes{closed->open} = %v, want 1", got)
+	}
+	// open_breakers went +1 on opening and -1 on leaving open.
+	if got := rec.sum("circuitbreaker.open_breakers", attribute.String("name", "db")); got != 0 {
+		t.Fatalf("open_breakers net = %v, want 0", got)
+	}
+}
+
+func TestMetricsAttachesResourceAttributes(t *testing.T) {
+	rec := &recorder{}
+	m, err := NewMetrics(&fakeMeter{rec: rec})
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+
+	m.RecordAttributes("db", map[string]string{"tier": "critical"})
+	m.RecordSuccess("db", time.Millisecond)
+	if got := rec.sum("circuitbreaker.requests", attribute.String("tier", "critical")); got != 1 {
+		t.Fatalf("requests{tier=critical} = %v, want 1", got)
+	}
+}
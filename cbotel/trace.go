@@ -0,0 +1,92 @@
+package cbotel
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+const tracerName = "github.com/NTbankey1/circuit-breaker/cbotel"
+
+// Span attribute keys recorded on breaker spans.
+const (
+	AttrBreakerName     = attribute.Key("circuitbreaker.name")
+	AttrBreakerState    = attribute.Key("circuitbreaker.state")
+	AttrBreakerDecision = attribute.Key("circuitbreaker.decision")
+	AttrBreakerOutcome  = attribute.Key("circuitbreaker.outcome")
+)
+
+// Do runs fn through b inside a span named after the breaker, recording
+// the breaker's state, the admission decision and the outcome — so traces
+// show exactly where a request was shed rather than just a mysterious
+// fast error. If tracer is nil the globally registered tracer provider is
+// used.
+func Do(ctx context.Context, tracer trace.Tracer, b *circuitbreaker.Breaker, fn func(ctx context.Context) error) error {
+	if tracer == nil {
+		tracer = otel.Tracer(tracerName)
+	}
+	attrs := []attribute.KeyValue{
+		AttrBreakerName.String(b.Name()),
+		AttrBreakerState.String(b.State().String()),
+	}
+	for k, v := range b.Attributes() {
+		attrs = append(attrs, attribute.String("circuitbreaker."+k, v))
+	}
+	ctx, span := tracer.Start(ctx, "circuitbreaker "+b.Name(),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	err := b.DoContext(ctx, fn)
+	switch {
+	case errors.Is(err, circuitbreaker.ErrOpen), errors.Is(err, circuitbreaker.ErrTooManyRequests):
+		span.SetAttributes(AttrBreakerDecision.String("rejected"))
+		span.SetStatus(codes.Error, err.Error())
+	case err != nil:
+		span.SetAttributes(
+			AttrBreakerDecision.String("allowed"),
+			AttrBreakerOutcome.String("failure"),
+		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		span.SetAttributes(
+			AttrBreakerDecision.String("allowed"),
+			AttrBreakerOutcome.String("success"),
+		)
+	}
+	return err
+}
+
+// Execute is the generic counterpart of Do, mirroring
+// circuitbreaker.Execute.
+func Execute[T any](ctx context.Context, tracer trace.Tracer, b *circuitbreaker.Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := Do(ctx, tracer, b, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// AddEvent attaches a breaker decision event to the span already active in
+// ctx, for callers who prefer events on their own spans over a nested
+// breaker span.
+func AddEvent(ctx context.Context, b *circuitbreaker.Breaker, err error) {
+	span := trace.SpanFromContext(ctx)
+	decision := "allowed"
+	if errors.Is(err, circuitbreaker.ErrOpen) || errors.Is(err, circuitbreaker.ErrTooManyRequests) {
+		decision = "rejected"
+	}
+	span.AddEvent("circuitbreaker.decision", trace.WithAttributes(
+		AttrBreakerName.String(b.Name()),
+		AttrBreakerState.String(b.State().String()),
+		AttrBreakerDecision.String(decision),
+	))
+}
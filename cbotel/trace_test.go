@@ -0,0 +1,140 @@
+package cbotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// fakeSpan extends the API's noop span to capture what Do records; see
+// the note on recorder in metrics_test.go.
+type fakeSpan struct {
+	noop.Span
+	name       string
+	attrs      []attribute.KeyValue
+	status     codes.Code
+	recorded   []error
+	ended      bool
+	statusDesc string
+}
+
+func (s *fakeSpan) SetAttributes(kv ...attribute.KeyValue) { s.attrs = append(s.attrs, kv...) }
+func (s *fakeSpan) End(...trace.SpanEndOption)             { s.ended = true }
+
+func (s *fakeSpan) SetStatus(code codes.Code, desc string) {
+	s.status = code
+	s.statusDesc = desc
+}
+
+func (s *fakeSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.recorded = append(s.recorded, err)
+}
+
+func (s *fakeSpan) attr(key attribute.Key) string {
+	for _, kv := range s.attrs {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+type fakeTracer struct {
+	noop.Tracer
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &fakeSpan{name: name, attrs: cfg.Attributes()}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func newBreaker(name string) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{Name: name, OpenTimeout: time.Minute})
+}
+
+func TestDoRecordsSuccess(t *testing.T) {
+	tracer := &fakeTracer{}
+	b := newBreaker("db")
+
+	if err := Do(context.Background(), tracer, b, func(ctx context.Context) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "circuitbreaker db" || !span.ended {
+		t.Fatalf("span = %q ended=%v", span.name, span.ended)
+	}
+	if got := span.attr(AttrBreakerDecision); got != "allowed" {
+		t.Fatalf("decision = %q, want allowed", got)
+	}
+	if got := span.attr(AttrBreakerOutcome); got != "success" {
+		t.Fatalf("outcome = %q, want success", got)
+	}
+	if span.status != codes.Unset {
+		t.Fatalf("status = %v, want unset", span.status)
+	}
+}
+
+func TestDoRecordsFailure(t *testing.T) {
+	tracer := &fakeTracer{}
+	b := newBreaker("db")
+	boom := errors.New("boom")
+
+	if err := Do(context.Background(), tracer, b, func(ctx context.Context) error {
+		return boom
+	}); !errors.Is(err, boom) {
+		t.Fatalf("Do = %v, want boom", err)
+	}
+
+	span := tracer.spans[0]
+	if got := span.attr(AttrBreakerOutcome); got != "failure" {
+		t.Fatalf("outcome = %q, want failure", got)
+	}
+	if span.status != codes.Error {
+		t.Fatalf("status = %v, want error", span.status)
+	}
+	if len(span.recorded) != 1 || !errors.Is(span.recorded[0], boom) {
+		t.Fatalf("recorded errors = %v, want [boom]", span.recorded)
+	}
+}
+
+func TestDoRecordsRejection(t *testing.T) {
+	tracer := &fakeTracer{}
+	b := newBreaker("db")
+	b.ForceOpen()
+
+	err := Do(context.Background(), tracer, b, func(ctx context.Context) error {
+		t.Fatal("fn ran despite the open circuit")
+		return nil
+	})
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Do = %v, want ErrOpen", err)
+	}
+
+	span := tracer.spans[0]
+	if got := span.attr(AttrBreakerDecision); got != "rejected" {
+		t.Fatalf("decision = %q, want rejected", got)
+	}
+	if got := span.attr(AttrBreakerState); got != "open" {
+		t.Fatalf("state attribute = %q, want open", got)
+	}
+	if span.status != codes.Error {
+		t.Fatalf("status = %v, want error", span.status)
+	}
+}
@@ -0,0 +1,134 @@
+// Package cbpgx integrates circuit breakers with pgx connection pools,
+// for teams on pgx rather than database/sql (which sqlbreaker covers).
+// Query outcomes feed the breaker through a pgx.QueryTracer, and pool
+// hooks reject acquisition while the circuit is open so callers fail fast
+// instead of queueing on a drowning database.
+package cbpgx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ErrSlowQuery is the failure recorded against the breaker when a query
+// succeeded but exceeded the slow-call threshold.
+var ErrSlowQuery = errors.New("cbpgx: slow query")
+
+// options carries the integration's configuration.
+type options struct {
+	slowThreshold time.Duration
+}
+
+// Option configures the integration.
+type Option func(*options)
+
+// WithSlowCallThreshold counts queries slower than d as failures —
+// recorded as ErrSlowQuery — even when they succeed.
+func WithSlowCallThreshold(d time.Duration) Option {
+	return func(o *options) { o.slowThreshold = d }
+}
+
+// Configure installs breaker integration into a pgxpool.Config, one
+// breaker per database:
+//
+//   - the connection config's Tracer records every query's outcome and
+//     latency in the breaker;
+//   - BeforeAcquire rejects acquisition while the circuit is open, so
+//     the caller's Acquire/Query fails instead of borrowing a connection
+//     to a database known to be down;
+//   - AfterRelease discards connections released while the circuit is
+//     open, so recovery starts from fresh connections.
+//
+// Existing hooks and tracer on cfg are chained, not replaced. Note that
+// pgxpool responds to a false BeforeAcquire by discarding that connection
+// and trying another, so an open circuit also drains the idle pool —
+// acceptable, since those connections point at a database the breaker
+// just declared unhealthy.
+func Configure(cfg *pgxpool.Config, breaker *circuitbreaker.Breaker, opts ...Option) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	beforeAcquire := cfg.BeforeAcquire
+	cfg.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		if breaker.State() == circuitbreaker.StateOpen {
+			breaker.ReportRejection(circuitbreaker.ReasonOpen)
+			return false
+		}
+		return beforeAcquire == nil || beforeAcquire(ctx, conn)
+	}
+	afterRelease := cfg.AfterRelease
+	cfg.AfterRelease = func(conn *pgx.Conn) bool {
+		if breaker.State() == circuitbreaker.StateOpen {
+			return false
+		}
+		return afterRelease == nil || afterRelease(conn)
+	}
+	cfg.ConnConfig.Tracer = &Tracer{breaker: breaker, opts: o, next: cfg.ConnConfig.Tracer}
+}
+
+// Tracer is a pgx.QueryTracer feeding query outcomes into a breaker. It
+// records post-hoc — a tracer cannot abort a query, admission control
+// lives in the pool hooks — so a query running while the breaker is open
+// (e.g. started just before the trip) simply drops its outcome.
+type Tracer struct {
+	breaker *circuitbreaker.Breaker
+	opts    options
+	next    pgx.QueryTracer
+}
+
+// NewTracer returns a Tracer for use outside Configure, e.g. on a plain
+// *pgx.Conn config.
+func NewTracer(breaker *circuitbreaker.Breaker, opts ...Option) *Tracer {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Tracer{breaker: breaker, opts: o}
+}
+
+// queryKey carries the in-flight query's outcome recorder through the
+// trace context.
+type queryKey struct{}
+
+type query struct {
+	done  func(error)
+	start time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if t.next != nil {
+		ctx = t.next.TraceQueryStart(ctx, conn, data)
+	}
+	if done, err := t.breaker.Allow(); err == nil {
+		ctx = context.WithValue(ctx, queryKey{}, &query{done: done, start: time.Now()})
+	}
+	return ctx
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *Tracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	if q, ok := ctx.Value(queryKey{}).(*query); ok {
+		switch {
+		case errors.Is(data.Err, context.Canceled):
+			// The caller gave up; that says nothing about the database.
+			q.done(nil)
+		case data.Err != nil:
+			q.done(data.Err)
+		case t.opts.slowThreshold > 0 && time.Since(q.start) > t.opts.slowThreshold:
+			q.done(ErrSlowQuery)
+		default:
+			q.done(nil)
+		}
+	}
+	if t.next != nil {
+		t.next.TraceQueryEnd(ctx, conn, data)
+	}
+}
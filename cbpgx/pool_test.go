@@ -0,0 +1,85 @@
+package cbpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+var errDB = errors.New("db down")
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+// traceQuery runs one query through the tracer as pgx would, ending it
+// with err after delay.
+func traceQuery(tracer *Tracer, delay time.Duration, err error) {
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	time.Sleep(delay)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+}
+
+func TestTracerRecordsOutcomes(t *testing.T) {
+	breaker := tripAfter(2)
+	tracer := NewTracer(breaker)
+
+	traceQuery(tracer, 0, nil)
+	traceQuery(tracer, 0, errDB)
+	traceQuery(tracer, 0, errDB)
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after two failures", got)
+	}
+	// The open transition starts a fresh generation, so inspect the
+	// counts captured at the moment the breaker tripped.
+	if got := breaker.TripCounts(); got.TotalSuccesses != 1 || got.TotalFailures != 2 {
+		t.Fatalf("trip counts = %+v, want 1 success / 2 failures", got)
+	}
+}
+
+func TestTracerDropsOutcomesWhileOpen(t *testing.T) {
+	breaker := tripAfter(1)
+	breaker.ForceOpen()
+	tracer := NewTracer(breaker)
+
+	// A tracer cannot abort a query; while the circuit is open it simply
+	// records nothing.
+	traceQuery(tracer, 0, errDB)
+	if got := breaker.Counts(); got.Requests != 0 {
+		t.Fatalf("Requests = %d, want 0", got.Requests)
+	}
+}
+
+func TestTracerSlowQueryCountsAsFailure(t *testing.T) {
+	breaker := tripAfter(1)
+	tracer := NewTracer(breaker, WithSlowCallThreshold(time.Millisecond))
+
+	traceQuery(tracer, 5*time.Millisecond, nil)
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after slow query", got)
+	}
+	samples := breaker.Status().RecentErrors
+	if len(samples) == 0 || samples[0].Error != ErrSlowQuery.Error() {
+		t.Fatalf("samples = %+v, want ErrSlowQuery", samples)
+	}
+}
+
+func TestTracerCanceledQueryIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	tracer := NewTracer(breaker)
+
+	traceQuery(tracer, 0, context.Canceled)
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (cancellation is neutral)", got)
+	}
+}
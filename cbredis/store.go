@@ -0,0 +1,82 @@
+// Package cbredis backs circuit breaker shared state with Redis, so every
+// instance of a service agrees on a dependency's circuit state.
+//
+// The package depends only on a small Client interface rather than a
+// concrete Redis library; *redis.Client from github.com/redis/go-redis
+// satisfies it via a thin adapter, and tests can supply an in-memory fake.
+package cbredis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ErrNil is what Client.Get must return (possibly wrapped) for a missing
+// key. Adapters over go-redis translate redis.Nil into it.
+var ErrNil = errors.New("cbredis: key does not exist")
+
+// Client is the minimal Redis surface the store needs.
+type Client interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+}
+
+// StateStore implements circuitbreaker.SharedStateStore on top of Redis.
+type StateStore struct {
+	client    Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// Option configures a StateStore.
+type Option func(*StateStore)
+
+// WithKeyPrefix sets the prefix for breaker state keys. The default is
+// "circuitbreaker:".
+func WithKeyPrefix(prefix string) Option {
+	return func(s *StateStore) { s.keyPrefix = prefix }
+}
+
+// WithTTL sets an expiry on stored state so that abandoned breakers do not
+// accumulate in Redis. The default is no expiry.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *StateStore) { s.ttl = ttl }
+}
+
+// NewStateStore returns a StateStore using client.
+func NewStateStore(client Client, opts ...Option) *StateStore {
+	s := &StateStore{client: client, keyPrefix: "circuitbreaker:"}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Load implements circuitbreaker.SharedStateStore.
+func (s *StateStore) Load(ctx context.Context, name string) (circuitbreaker.SharedState, error) {
+	var state circuitbreaker.SharedState
+	raw, err := s.client.Get(ctx, s.keyPrefix+name)
+	if err != nil {
+		if errors.Is(err, ErrNil) {
+			return state, circuitbreaker.ErrNoSharedState
+		}
+		return state, err
+	}
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// Store implements circuitbreaker.SharedStateStore.
+func (s *StateStore) Store(ctx context.Context, name string, state circuitbreaker.SharedState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.keyPrefix+name, string(raw), s.ttl)
+}
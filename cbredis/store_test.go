@@ -0,0 +1,85 @@
+package cbredis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// fakeClient is the in-memory Client the package doc promises tests can
+// supply.
+type fakeClient struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{values: make(map[string]string)}
+}
+
+func (c *fakeClient) Get(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		return "", ErrNil
+	}
+	return value, nil
+}
+
+func (c *fakeClient) Set(_ context.Context, key, value string, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func TestStateStoreRoundTrip(t *testing.T) {
+	store := NewStateStore(newFakeClient())
+	want := circuitbreaker.SharedState{
+		State:  circuitbreaker.StateOpen,
+		Counts: circuitbreaker.Counts{Requests: 7, TotalFailures: 7},
+		Expiry: time.Now().Add(time.Minute).Truncate(time.Second),
+	}
+	if err := store.Store(context.Background(), "db", want); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	got, err := store.Load(context.Background(), "db")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.State != want.State || got.Counts != want.Counts || !got.Expiry.Equal(want.Expiry) {
+		t.Fatalf("Load = %+v, want %+v", got, want)
+	}
+}
+
+func TestStateStoreMissingKey(t *testing.T) {
+	store := NewStateStore(newFakeClient())
+	_, err := store.Load(context.Background(), "nope")
+	if !errors.Is(err, circuitbreaker.ErrNoSharedState) {
+		t.Fatalf("Load = %v, want ErrNoSharedState", err)
+	}
+}
+
+func TestStateStoreKeyPrefix(t *testing.T) {
+	client := newFakeClient()
+	store := NewStateStore(client, WithKeyPrefix("cb:"))
+	if err := store.Store(context.Background(), "db", circuitbreaker.SharedState{}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if _, ok := client.values["cb:db"]; !ok {
+		t.Fatalf("stored keys = %v, want cb:db", keys(client.values))
+	}
+}
+
+func keys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package cbtwirp
+
+import (
+	"net/http"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+	"github.com/NTbankey1/circuit-breaker/cbhttp"
+)
+
+// NewClient returns a cbhttp.HTTPClient pre-configured for Twirp's error
+// model, for passing as the HTTPClient of a generated Twirp client
+// constructor. Twirp maps server-fault codes onto HTTP statuses — 5xx for
+// internal/unavailable, 408 for deadline_exceeded, 429 for
+// resource_exhausted — and those count against the breaker; client-fault
+// codes land in the remaining 4xx range and are neutral. opts compose on
+// top (retries, per-host breakers, observers, ...).
+func NewClient(breaker *circuitbreaker.Breaker, opts ...cbhttp.Option) *cbhttp.HTTPClient {
+	defaults := []cbhttp.Option{cbhttp.WithIsSuccessful(twirpIsSuccessful)}
+	return cbhttp.NewHTTPClient(breaker, append(defaults, opts...)...)
+}
+
+// twirpIsSuccessful classifies a Twirp response for the breaker.
+func twirpIsSuccessful(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode >= 500:
+		return false
+	case resp.StatusCode == http.StatusRequestTimeout, // deadline_exceeded
+		resp.StatusCode == http.StatusTooManyRequests: // resource_exhausted
+		return false
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+// Package cbtwirp integrates circuit breakers with Twirp services:
+// server hooks admitting each RPC through a breaker, and a client
+// constructor pre-configured for Twirp's error model.
+package cbtwirp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/twitchtv/twirp"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// config carries the hooks' configuration.
+type config struct {
+	breaker  *circuitbreaker.Breaker
+	registry *circuitbreaker.Registry
+}
+
+// Option configures the server hooks.
+type Option func(*config)
+
+// WithMethodBreakers keys the hooks by "Service/Method" instead of one
+// breaker for the whole server. Breakers come from registry, created on
+// demand with its defaults.
+func WithMethodBreakers(registry *circuitbreaker.Registry) Option {
+	return func(c *config) { c.registry = registry }
+}
+
+// callKey carries the in-flight call's outcome recorder through the hook
+// context.
+type callKey struct{}
+
+type call struct {
+	done func(error)
+	once sync.Once
+}
+
+func (c *call) finish(err error) {
+	c.once.Do(func() { c.done(err) })
+}
+
+// NewServerHooks returns twirp.ServerHooks admitting each routed RPC
+// through breaker. Shed RPCs fail fast with twirp.Unavailable; outcomes
+// are recorded from the Error and ResponseSent hooks, with client-fault
+// codes (NotFound, InvalidArgument, ...) counting as neutral — only
+// server-fault codes push the breaker toward open. The breaker may be nil
+// if WithMethodBreakers is used.
+func NewServerHooks(breaker *circuitbreaker.Breaker, opts ...Option) *twirp.ServerHooks {
+	c := &config{breaker: breaker}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			done, err := c.breakerFor(ctx).AllowContext(ctx)
+			if err != nil {
+				return ctx, twirp.NewError(twirp.Unavailable, "service temporarily unavailable")
+			}
+			return context.WithValue(ctx, callKey{}, &call{done: done}), nil
+		},
+		Error: func(ctx context.Context, err twirp.Error) context.Context {
+			if cl, ok := ctx.Value(callKey{}).(*call); ok {
+				if failureCode(err.Code()) {
+					cl.finish(err)
+				} else {
+					cl.finish(nil)
+				}
+			}
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			if cl, ok := ctx.Value(callKey{}).(*call); ok {
+				cl.finish(nil)
+			}
+		},
+	}
+}
+
+func (c *config) breakerFor(ctx context.Context) *circuitbreaker.Breaker {
+	if c.registry != nil {
+		service, _ := twirp.ServiceName(ctx)
+		method, _ := twirp.MethodName(ctx)
+		return c.registry.Get(service + "/" + method)
+	}
+	return c.breaker
+}
+
+// failureCode reports whether a twirp error code means the server (or
+// what it depends on) is in trouble, as opposed to the caller having
+// asked for something wrong.
+func failureCode(code twirp.ErrorCode) bool {
+	switch code {
+	case twirp.Internal, twirp.Unavailable, twirp.DeadlineExceeded,
+		twirp.ResourceExhausted, twirp.DataLoss, twirp.Unknown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package cbtwirp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twitchtv/twirp"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+// route runs one RPC through the hooks the way a twirp server would:
+// RequestRouted, then either the Error hook or ResponseSent.
+func route(t *testing.T, hooks *twirp.ServerHooks, rpcErr twirp.Error) error {
+	t.Helper()
+	ctx, err := hooks.RequestRouted(context.Background())
+	if err != nil {
+		return err
+	}
+	if rpcErr != nil {
+		hooks.Error(ctx, rpcErr)
+		return rpcErr
+	}
+	hooks.ResponseSent(ctx)
+	return nil
+}
+
+func TestHooksTripAndShed(t *testing.T) {
+	breaker := tripAfter(2)
+	hooks := NewServerHooks(breaker)
+
+	for i := 0; i < 2; i++ {
+		route(t, hooks, twirp.NewError(twirp.Internal, "boom"))
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+
+	err := route(t, hooks, nil)
+	var te twirp.Error
+	if !errors.As(err, &te) || te.Code() != twirp.Unavailable {
+		t.Fatalf("shed RPC = %v, want twirp.Unavailable", err)
+	}
+}
+
+func TestHooksClientFaultIsNeutral(t *testing.T) {
+	breaker := tripAfter(1)
+	hooks := NewServerHooks(breaker)
+
+	route(t, hooks, twirp.NewError(twirp.NotFound, "no such user"))
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (NotFound is the caller's problem)", got)
+	}
+	if got := breaker.Counts(); got.TotalSuccesses != 1 {
+		t.Fatalf("TotalSuccesses = %d, want 1", got.TotalSuccesses)
+	}
+}
+
+func TestHooksRecordSuccessOnce(t *testing.T) {
+	// A threshold of 2 keeps the breaker closed, so the counts below are
+	// not reset by a trip's new generation.
+	breaker := tripAfter(2)
+	hooks := NewServerHooks(breaker)
+
+	// A served response that also passed through the Error hook (e.g.
+	// error written, then ResponseSent fires) must record one outcome,
+	// not two.
+	ctx, err := hooks.RequestRouted(context.Background())
+	if err != nil {
+		t.Fatalf("RequestRouted: %v", err)
+	}
+	hooks.Error(ctx, twirp.NewError(twirp.Internal, "boom"))
+	hooks.ResponseSent(ctx)
+
+	if got := breaker.Counts(); got.Requests != 1 || got.TotalFailures != 1 {
+		t.Fatalf("counts = %+v, want exactly one recorded failure", got)
+	}
+}
@@ -0,0 +1,80 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// Failure categories produced by DefaultClassifyError. Classifiers must
+// map errors onto a bounded set of values — the category becomes a metric
+// label.
+const (
+	CategoryTimeout     = "timeout"
+	CategoryConnRefused = "conn_refused"
+	CategoryServer      = "5xx"
+	CategoryOther       = "other"
+)
+
+// FailureCategoryRecorder is an optional interface a MetricsCollector may
+// implement to receive failures broken down by error category, in addition
+// to the plain RecordFailure call, so root-cause triage is possible from
+// metrics alone.
+type FailureCategoryRecorder interface {
+	RecordFailureCategory(name, category string)
+}
+
+// httpStatusError is implemented by errors carrying an HTTP status code,
+// such as the status errors returned by the HTTP client helpers.
+type httpStatusError interface {
+	HTTPStatus() int
+}
+
+// WeightByCategory returns a Config.FailureWeight that classifies each
+// failure with classify (DefaultClassifyError if nil) and looks its weight
+// up in weights. Categories not present weigh one, so e.g.
+//
+//	FailureWeight: WeightByCategory(map[string]uint64{
+//		CategoryTimeout:     3,
+//		CategoryConnRefused: 3,
+//	}, nil)
+//
+// trips the breaker three times faster on network trouble than on 5xx
+// responses.
+func WeightByCategory(weights map[string]uint64, classify func(err error) string) func(err error) uint64 {
+	if classify == nil {
+		classify = DefaultClassifyError
+	}
+	return func(err error) uint64 {
+		if weight, ok := weights[classify(err)]; ok {
+			return weight
+		}
+		return 1
+	}
+}
+
+// DefaultClassifyError maps err to one of the Category constants. It
+// recognizes timeouts (net.Error, context.DeadlineExceeded), refused
+// connections and errors carrying a 5xx HTTP status; everything else is
+// CategoryOther.
+func DefaultClassifyError(err error) string {
+	if err == nil {
+		return CategoryOther
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return CategoryTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return CategoryConnRefused
+	}
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) && statusErr.HTTPStatus() >= 500 {
+		return CategoryServer
+	}
+	return CategoryOther
+}
@@ -0,0 +1,63 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type statusErr int
+
+func (e statusErr) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusErr) HTTPStatus() int { return int(e) }
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestDefaultClassifyError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{nil, CategoryOther},
+		{errors.New("boom"), CategoryOther},
+		{context.DeadlineExceeded, CategoryTimeout},
+		{&net.OpError{Op: "read", Err: timeoutErr{}}, CategoryTimeout},
+		{&os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}, CategoryConnRefused},
+		{statusErr(503), CategoryServer},
+		{statusErr(404), CategoryOther},
+	}
+	for _, tc := range cases {
+		if got := DefaultClassifyError(tc.err); got != tc.want {
+			t.Errorf("DefaultClassifyError(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWeightByCategory(t *testing.T) {
+	weight := WeightByCategory(map[string]uint64{
+		CategoryTimeout: 3,
+	}, nil)
+	if got := weight(context.DeadlineExceeded); got != 3 {
+		t.Fatalf("weight(timeout) = %d, want 3", got)
+	}
+	if got := weight(errors.New("boom")); got != 1 {
+		t.Fatalf("weight(other) = %d, want 1 (unlisted categories weigh one)", got)
+	}
+}
+
+func TestWeightByCategoryCustomClassifier(t *testing.T) {
+	weight := WeightByCategory(map[string]uint64{"db": 5}, func(err error) string {
+		return "db"
+	})
+	if got := weight(errors.New("anything")); got != 5 {
+		t.Fatalf("weight = %d, want 5 via custom classifier", got)
+	}
+}
@@ -0,0 +1,94 @@
+// Command cb-dashboards emits a Grafana dashboard JSON covering the
+// metrics exported by the prom package — breaker states, failure rate,
+// rejections, latency and transitions — parameterized by the metrics
+// namespace, so a team gets a working dashboard in one command:
+//
+//	cb-dashboards -namespace circuitbreaker > circuit-breakers.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	namespace := flag.String("namespace", "circuitbreaker", "metrics namespace used when constructing prom.Metrics")
+	title := flag.String("title", "Circuit Breakers", "dashboard title")
+	datasource := flag.String("datasource", "${DS_PROMETHEUS}", "Grafana datasource uid or variable")
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	dashboard := buildDashboard(*namespace, *title, *datasource)
+	data, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cb-dashboards:", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "cb-dashboards:", err)
+		os.Exit(1)
+	}
+}
+
+func buildDashboard(namespace, title, datasource string) map[string]any {
+	panels := []map[string]any{
+		panel(1, "Breakers by state", 0, 0,
+			target(datasource, `circuitbreaker_breakers`, "{{state}}")),
+		panel(2, "Failure rate", 12, 0,
+			target(datasource,
+				fmt.Sprintf(`sum by (name) (rate(%s_requests_total{outcome="failure"}[5m])) / sum by (name) (rate(%s_requests_total[5m]))`, namespace, namespace),
+				"{{name}}")),
+		panel(3, "Rejections by reason", 0, 8,
+			target(datasource,
+				fmt.Sprintf(`sum by (name, reason) (rate(%s_rejections_total[5m]))`, namespace),
+				"{{name}} ({{reason}})")),
+		panel(4, "Request latency (p95)", 12, 8,
+			target(datasource,
+				fmt.Sprintf(`histogram_quantile(0.95, sum by (name, le) (rate(%s_request_duration_seconds_bucket[5m])))`, namespace),
+				"{{name}}")),
+		panel(5, "State transitions", 0, 16,
+			target(datasource,
+				fmt.Sprintf(`sum by (name, to) (rate(%s_state_changes_total[5m]))`, namespace),
+				"{{name}} → {{to}}")),
+		panel(6, "Half-open probe results", 12, 16,
+			target(datasource,
+				fmt.Sprintf(`sum by (name, outcome) (rate(%s_halfopen_probe_results_total[5m]))`, namespace),
+				"{{name}} ({{outcome}})")),
+	}
+	return map[string]any{
+		"title":         title,
+		"uid":           "circuit-breakers",
+		"schemaVersion": 39,
+		"tags":          []string{"circuit-breaker", "generated"},
+		"timezone":      "browser",
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+	}
+}
+
+func panel(id int, title string, x, y int, targets ...map[string]any) map[string]any {
+	return map[string]any{
+		"id":      id,
+		"type":    "timeseries",
+		"title":   title,
+		"gridPos": map[string]int{"x": x, "y": y, "w": 12, "h": 8},
+		"targets": targets,
+	}
+}
+
+func target(datasource, expr, legend string) map[string]any {
+	return map[string]any{
+		"datasource":   map[string]string{"type": "prometheus", "uid": datasource},
+		"expr":         expr,
+		"legendFormat": legend,
+	}
+}
@@ -0,0 +1,114 @@
+package circuitbreaker
+
+import "time"
+
+const (
+	defaultOpenTimeout         = 60 * time.Second
+	defaultMaxHalfOpenRequests = 1
+	defaultTripThreshold       = 5
+)
+
+// Config configures a Breaker. The zero value is usable: the breaker trips
+// after defaultTripThreshold consecutive failures, stays open for
+// defaultOpenTimeout and allows a single half-open probe.
+type Config struct {
+	// Name identifies the breaker in logs, metrics and the registry.
+	Name string
+
+	// MaxHalfOpenRequests is the number of concurrent probe requests
+	// allowed while the breaker is half-open. Defaults to 1.
+	MaxHalfOpenRequests uint64
+
+	// OpenTimeout is how long the breaker stays open before transitioning
+	// to half-open. Defaults to 60s.
+	OpenTimeout time.Duration
+
+	// Interval is the period after which closed-state counts are reset.
+	// If zero, counts accumulate for the lifetime of the closed state.
+	Interval time.Duration
+
+	// WindowSize, if positive, evaluates the trip condition over a
+	// rolling window of this length instead of the counts since the last
+	// reset: the Counts passed to ReadyToTrip then carry the window's
+	// request/success/failure totals (consecutive counts remain live).
+	WindowSize time.Duration
+
+	// WindowBuckets is the number of buckets the window is divided into.
+	// Defaults to 10. More buckets roll off expired outcomes more
+	// smoothly at slightly more memory.
+	WindowBuckets int
+
+	// ReadyToTrip is consulted after each failure in the closed state;
+	// returning true opens the circuit. If nil, the breaker trips once
+	// ConsecutiveFailures exceeds defaultTripThreshold.
+	ReadyToTrip func(counts Counts) bool
+
+	// IsSuccessful classifies the error returned by a request. If nil,
+	// any non-nil error counts as a failure.
+	IsSuccessful func(err error) bool
+
+	// OnStateChange, if set, is called synchronously whenever the breaker
+	// changes state.
+	OnStateChange func(name string, from, to State)
+
+	// Metrics, if set, receives the breaker's request outcomes and state
+	// changes. See MetricsCollector.
+	Metrics MetricsCollector
+
+	// HistorySize bounds the in-memory ring of recent state transitions
+	// kept per breaker; see Breaker.History. Defaults to 10.
+	HistorySize int
+
+	// FailureSamples bounds the reservoir of recent failure errors kept
+	// per breaker; see Breaker.RecentFailures. Defaults to 5.
+	FailureSamples int
+
+	// ClassifyError maps a failure to a bounded category for metrics; it
+	// is consulted only when Metrics implements FailureCategoryRecorder.
+	// If nil, DefaultClassifyError is used.
+	ClassifyError func(err error) string
+
+	// FailureWeight, if set, returns the weight of a failure: the failure
+	// and consecutive-failure counts grow by that amount instead of one,
+	// so e.g. network timeouts can trip the breaker faster than 5xx
+	// responses (see WeightByCategory). A weight of zero still resets
+	// ConsecutiveSuccesses but adds nothing to the failure counts.
+	FailureWeight func(err error) uint64
+
+	// Attributes are resource attributes describing the breaker — team,
+	// tier, dependency type — propagated to metrics backends that
+	// implement AttributeRecorder and surfaced in BreakerStatus, so
+	// alerts can be routed to the owning team. Keys should come from a
+	// small fixed set; values must not be unbounded.
+	Attributes map[string]string
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxHalfOpenRequests == 0 {
+		c.MaxHalfOpenRequests = defaultMaxHalfOpenRequests
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = defaultOpenTimeout
+	}
+	if c.ReadyToTrip == nil {
+		c.ReadyToTrip = func(counts Counts) bool {
+			return counts.ConsecutiveFailures > defaultTripThreshold
+		}
+	}
+	if c.IsSuccessful == nil {
+		c.IsSuccessful = func(err error) bool { return err == nil }
+	}
+	if c.ClassifyError == nil {
+		c.ClassifyError = DefaultClassifyError
+	}
+	if c.HistorySize <= 0 {
+		c.HistorySize = defaultHistorySize
+	}
+	if c.FailureSamples <= 0 {
+		c.FailureSamples = defaultFailureSamples
+	}
+	if c.WindowSize > 0 && c.WindowBuckets <= 0 {
+		c.WindowBuckets = defaultWindowBuckets
+	}
+	return c
+}
@@ -0,0 +1,90 @@
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Control commands let a central operator flip breakers across a whole
+// fleet at once — a manual kill-switch for a failing provider. Commands are
+// JSON payloads published on any pub/sub transport (NATS, Redis pub/sub);
+// each instance feeds received payloads into a ControlListener, which
+// applies them to its registry immediately.
+
+// Control command actions.
+const (
+	ControlForceOpen  = "force-open"
+	ControlForceClose = "force-close"
+	ControlReset      = "reset"
+)
+
+// ControlCommand is the wire format of a control message. Breaker may be a
+// glob pattern ("db-*") to address several breakers at once.
+type ControlCommand struct {
+	Action  string `json:"action"`
+	Breaker string `json:"breaker"`
+}
+
+// ApplyControl applies cmd to every matching breaker and returns how many
+// were affected. Unknown actions return an error.
+func (r *Registry) ApplyControl(cmd ControlCommand) (int, error) {
+	matched := r.Find(cmd.Breaker)
+	for _, b := range matched {
+		switch cmd.Action {
+		case ControlForceOpen:
+			b.ForceOpen()
+		case ControlForceClose:
+			b.ForceClose()
+		case ControlReset:
+			b.Reset()
+		default:
+			return 0, fmt.Errorf("circuitbreaker: unknown control action %q", cmd.Action)
+		}
+	}
+	return len(matched), nil
+}
+
+// ControlListener applies published control commands to a registry. Wire it
+// to a transport by feeding message payloads into Listen — msg.Data for a
+// NATS subscription, []byte(msg.Payload) for go-redis pub/sub.
+type ControlListener struct {
+	registry *Registry
+
+	// OnError, if set, is called when a payload cannot be parsed or
+	// applied. By default such payloads are dropped silently.
+	OnError func(payload []byte, err error)
+}
+
+// NewControlListener returns a listener applying commands to registry.
+func NewControlListener(registry *Registry) *ControlListener {
+	return &ControlListener{registry: registry}
+}
+
+// Handle parses and applies a single control payload.
+func (l *ControlListener) Handle(payload []byte) error {
+	var cmd ControlCommand
+	if err := json.Unmarshal(payload, &cmd); err != nil {
+		return fmt.Errorf("circuitbreaker: bad control payload: %w", err)
+	}
+	_, err := l.registry.ApplyControl(cmd)
+	return err
+}
+
+// Listen applies every payload received on messages until the channel is
+// closed or ctx is done.
+func (l *ControlListener) Listen(ctx context.Context, messages <-chan []byte) {
+	for {
+		select {
+		case payload, ok := <-messages:
+			if !ok {
+				return
+			}
+			if err := l.Handle(payload); err != nil && l.OnError != nil {
+				l.OnError(payload, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
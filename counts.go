@@ -0,0 +1,47 @@
+package circuitbreaker
+
+// Counts holds the request outcome totals for the breaker's current
+// generation. A generation starts when the breaker changes state or when the
+// closed-state counts are reset after Config.Interval elapses.
+type Counts struct {
+	Requests             uint64
+	TotalSuccesses       uint64
+	TotalFailures        uint64
+	ConsecutiveSuccesses uint64
+	ConsecutiveFailures  uint64
+}
+
+func (c *Counts) onRequest() {
+	c.Requests++
+}
+
+func (c *Counts) onSuccess() {
+	c.TotalSuccesses++
+	c.ConsecutiveSuccesses++
+	c.ConsecutiveFailures = 0
+}
+
+func (c *Counts) onFailure() {
+	c.onWeightedFailure(1)
+}
+
+// onWeightedFailure records a failure counting as weight failures; see
+// Config.FailureWeight.
+func (c *Counts) onWeightedFailure(weight uint64) {
+	c.TotalFailures += weight
+	c.ConsecutiveFailures += weight
+	c.ConsecutiveSuccesses = 0
+}
+
+func (c *Counts) clear() {
+	*c = Counts{}
+}
+
+// FailureRate returns the fraction of requests in this generation that
+// failed, or 0 if no requests have been recorded.
+func (c Counts) FailureRate() float64 {
+	if c.Requests == 0 {
+		return 0
+	}
+	return float64(c.TotalFailures) / float64(c.Requests)
+}
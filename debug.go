@@ -0,0 +1,60 @@
+package circuitbreaker
+
+import "time"
+
+// DebugState is a full dump of a breaker's internal state for deep
+// troubleshooting: unlike BreakerStatus it exposes implementation details
+// — generation, expiry, raw window buckets — that can change between
+// releases. See admin.DebugHandler for the /debug/circuitbreaker endpoint.
+type DebugState struct {
+	Name       string
+	State      State
+	Forced     bool
+	Generation uint64
+	Counts     Counts
+	Rejections uint64
+	Opens      uint64
+	StateSince time.Time
+	Expiry     time.Time // when the current generation ends; zero if never
+
+	MaxHalfOpenRequests uint64
+	OpenTimeout         time.Duration
+	Interval            time.Duration
+
+	WindowSize     time.Duration
+	WindowBuckets  []Counts // raw bucket contents, oldest data mixed in ring order
+	WindowCurrent  int      // index of the bucket currently being written
+	PendingEvents  int      // transitions queued but not yet announced
+	HistoryLen     int
+	FailureSamples int
+}
+
+// DebugState returns the breaker's full internal state at this instant.
+func (b *Breaker) DebugState() DebugState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := DebugState{
+		Name:                b.cfg.Name,
+		State:               b.state,
+		Forced:              b.forced,
+		Generation:          b.generation,
+		Counts:              b.counts,
+		Rejections:          b.rejections,
+		Opens:               b.opens,
+		StateSince:          b.stateSince,
+		Expiry:              b.expiry,
+		MaxHalfOpenRequests: b.cfg.MaxHalfOpenRequests,
+		OpenTimeout:         b.cfg.OpenTimeout,
+		Interval:            b.cfg.Interval,
+		WindowSize:          b.cfg.WindowSize,
+		PendingEvents:       len(b.pending),
+		HistoryLen:          len(b.history),
+		FailureSamples:      len(b.failures),
+	}
+	if b.window != nil {
+		state.WindowBuckets = make([]Counts, len(b.window.buckets))
+		copy(state.WindowBuckets, b.window.buckets)
+		state.WindowCurrent = b.window.current
+	}
+	return state
+}
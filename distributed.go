@@ -0,0 +1,92 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SharedState is the portion of a breaker's state that can be shared across
+// service instances through a SharedStateStore.
+type SharedState struct {
+	State  State     `json:"state"`
+	Counts Counts    `json:"counts"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// ErrNoSharedState is returned by a SharedStateStore when no state has been
+// stored yet for a breaker; the DistributedBreaker then falls back to its
+// local state.
+var ErrNoSharedState = errors.New("circuitbreaker: no shared state stored")
+
+// SharedStateStore persists breaker state in a backend visible to every
+// instance of a service, such as Redis. Implementations must be safe for
+// concurrent use.
+type SharedStateStore interface {
+	// Load returns the shared state for the named breaker, or
+	// ErrNoSharedState if none exists.
+	Load(ctx context.Context, name string) (SharedState, error)
+	// Store writes the shared state for the named breaker.
+	Store(ctx context.Context, name string, state SharedState) error
+}
+
+// DistributedBreaker is a Breaker whose state and counts are synchronized
+// through a SharedStateStore before and after each request, so multiple
+// instances of a service agree on a dependency's circuit state instead of
+// each discovering an outage independently.
+//
+// If the store is unreachable the breaker degrades to its local state, so a
+// store outage never takes the guarded dependency down with it.
+type DistributedBreaker struct {
+	*Breaker
+	store SharedStateStore
+}
+
+// NewDistributedBreaker returns a breaker configured by cfg that shares its
+// state through store.
+func NewDistributedBreaker(cfg Config, store SharedStateStore) *DistributedBreaker {
+	return &DistributedBreaker{Breaker: New(cfg), store: store}
+}
+
+// Do runs fn if the breaker allows it, synchronizing shared state around
+// the request. See Breaker.Do for the rejection semantics.
+func (db *DistributedBreaker) Do(ctx context.Context, fn func() error) error {
+	db.pull(ctx)
+	err := db.Breaker.Do(fn)
+	db.push(ctx)
+	return err
+}
+
+// pull merges the stored shared state into the local breaker.
+func (db *DistributedBreaker) pull(ctx context.Context) {
+	shared, err := db.store.Load(ctx, db.Name())
+	if err != nil {
+		return
+	}
+	b := db.Breaker
+	b.mu.Lock()
+	if b.forced {
+		b.mu.Unlock()
+		return
+	}
+	if shared.State != b.state {
+		// setState keeps the transition history, opens counter and
+		// generation bookkeeping honest on followers; it also resets
+		// counts and expiry, which the shared values then overwrite.
+		b.setState(shared.State, time.Now())
+	}
+	b.counts = shared.Counts
+	b.expiry = shared.Expiry
+	pending := b.takePending()
+	b.mu.Unlock()
+	b.announce(pending)
+}
+
+// push writes the local breaker state back to the store.
+func (db *DistributedBreaker) push(ctx context.Context) {
+	b := db.Breaker
+	b.mu.Lock()
+	shared := SharedState{State: b.state, Counts: b.counts, Expiry: b.expiry}
+	b.mu.Unlock()
+	_ = db.store.Store(ctx, db.Name(), shared)
+}
@@ -0,0 +1,96 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in-memory SharedStateStore for tests.
+type memoryStore struct {
+	mu     sync.Mutex
+	states map[string]SharedState
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{states: make(map[string]SharedState)}
+}
+
+func (s *memoryStore) Load(_ context.Context, name string) (SharedState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	if !ok {
+		return SharedState{}, ErrNoSharedState
+	}
+	return state, nil
+}
+
+func (s *memoryStore) Store(_ context.Context, name string, state SharedState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[name] = state
+	return nil
+}
+
+func TestDistributedBreakerAdoptsRemoteOpen(t *testing.T) {
+	store := newMemoryStore()
+	store.Store(context.Background(), "db", SharedState{
+		State:  StateOpen,
+		Expiry: time.Now().Add(time.Minute),
+	})
+	db := NewDistributedBreaker(Config{Name: "db", OpenTimeout: time.Minute}, store)
+
+	err := db.Do(context.Background(), func() error { return nil })
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("Do = %v, want ErrOpen (remote state adopted)", err)
+	}
+	// Adoption goes through setState, so followers keep honest books.
+	if got := db.Opens(); got != 1 {
+		t.Fatalf("Opens = %d, want 1", got)
+	}
+	status := db.Status()
+	if n := len(status.RecentTransitions); n != 1 {
+		t.Fatalf("RecentTransitions has %d entries, want 1", n)
+	}
+	if tr := status.RecentTransitions[0]; tr.From != StateClosed || tr.To != StateOpen {
+		t.Fatalf("transition = %v>%v, want closed>open", tr.From, tr.To)
+	}
+}
+
+func TestDistributedBreakerPushesLocalState(t *testing.T) {
+	store := newMemoryStore()
+	db := NewDistributedBreaker(Config{
+		Name:        "db",
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+	}, store)
+
+	db.Do(context.Background(), func() error { return errFail })
+	shared, err := store.Load(context.Background(), "db")
+	if err != nil {
+		t.Fatalf("Load after push: %v", err)
+	}
+	if shared.State != StateOpen {
+		t.Fatalf("stored state = %v, want open", shared.State)
+	}
+}
+
+func TestDistributedBreakerSurvivesStoreOutage(t *testing.T) {
+	db := NewDistributedBreaker(Config{Name: "db"}, failingStore{})
+	if err := db.Do(context.Background(), func() error { return nil }); err != nil {
+		t.Fatalf("Do with unreachable store = %v, want nil", err)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Load(context.Context, string) (SharedState, error) {
+	return SharedState{}, errors.New("store down")
+}
+
+func (failingStore) Store(context.Context, string, SharedState) error {
+	return errors.New("store down")
+}
@@ -0,0 +1,71 @@
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ExportEntry is the serialized form of one breaker in an Export: its live
+// state snapshot plus the tunable configuration scalars, so hand-tuned
+// runtime overrides survive a blue/green hand-off. Behavioural hooks
+// (ReadyToTrip, IsSuccessful, OnStateChange) are not serializable and come
+// from the importing registry's defaults.
+type ExportEntry struct {
+	Snapshot
+	Rejections          uint64        `json:"rejections"`
+	MaxHalfOpenRequests uint64        `json:"max_half_open_requests"`
+	OpenTimeout         time.Duration `json:"open_timeout"`
+	Interval            time.Duration `json:"interval"`
+}
+
+// Export writes the registry's breakers — configuration scalars and live
+// state — as JSON to w.
+func (r *Registry) Export(w io.Writer) error {
+	breakers := r.All()
+	entries := make([]ExportEntry, 0, len(breakers))
+	for _, b := range breakers {
+		b.mu.Lock()
+		entry := ExportEntry{
+			Snapshot: Snapshot{
+				Name:   b.cfg.Name,
+				State:  b.state,
+				Counts: b.counts,
+				Expiry: b.expiry,
+				Taken:  time.Now(),
+			},
+			Rejections:          b.rejections,
+			MaxHalfOpenRequests: b.cfg.MaxHalfOpenRequests,
+			OpenTimeout:         b.cfg.OpenTimeout,
+			Interval:            b.cfg.Interval,
+		}
+		b.mu.Unlock()
+		entries = append(entries, entry)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// Import reads entries written by Export and applies them, creating
+// breakers that do not exist yet. Imported configuration scalars override
+// the registry defaults for newly created breakers; hooks always come from
+// the defaults.
+func (r *Registry) Import(reader io.Reader) error {
+	var entries []ExportEntry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		cfg := r.defaults
+		cfg.MaxHalfOpenRequests = entry.MaxHalfOpenRequests
+		cfg.OpenTimeout = entry.OpenTimeout
+		cfg.Interval = entry.Interval
+		b := r.GetWithConfig(entry.Name, cfg)
+		b.Restore(entry.Snapshot)
+		b.mu.Lock()
+		b.rejections = entry.Rejections
+		b.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package circuitbreaker
+
+import "time"
+
+const defaultFailureSamples = 5
+
+// FailureSample is one recorded failure: when it happened and the error's
+// message.
+type FailureSample struct {
+	At    time.Time `json:"at"`
+	Error string    `json:"error"`
+}
+
+// LastError returns the message of the most recent failure recorded by the
+// breaker, or "" if none has been recorded. Combined with History it
+// answers "why did this open?" without log correlation.
+func (b *Breaker) LastError() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastError
+}
+
+// RecentFailures returns the breaker's bounded reservoir of recent failure
+// samples, oldest first (Config.FailureSamples entries).
+func (b *Breaker) RecentFailures() []FailureSample {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	samples := make([]FailureSample, len(b.failures))
+	copy(samples, b.failures)
+	return samples
+}
+
+// recordFailureSample notes a failure's error for LastError and the
+// reservoir. Callers must hold b.mu.
+func (b *Breaker) recordFailureSample(err error, now time.Time) {
+	if err == nil {
+		return
+	}
+	b.lastError = err.Error()
+	size := b.cfg.FailureSamples
+	if len(b.failures) >= size {
+		b.failures = append(b.failures[:0], b.failures[len(b.failures)-size+1:]...)
+	}
+	b.failures = append(b.failures, FailureSample{At: now, Error: err.Error()})
+}
@@ -0,0 +1,124 @@
+package circuitbreaker
+
+// HealthStatus is the overall health of a registry as derived from its
+// breakers, suitable for wiring into readiness probes.
+type HealthStatus int
+
+const (
+	// Healthy means no rule found a problem.
+	Healthy HealthStatus = iota
+	// Degraded means some dependencies are impaired but the service can
+	// still do useful work; a readiness probe would typically still pass.
+	Degraded
+	// Unhealthy means the service should be taken out of rotation.
+	Unhealthy
+)
+
+// String returns a human-readable name for the status.
+func (s HealthStatus) String() string {
+	switch s {
+	case Healthy:
+		return "healthy"
+	case Degraded:
+		return "degraded"
+	case Unhealthy:
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthReport summarizes a registry's health.
+type HealthReport struct {
+	Status HealthStatus `json:"status"`
+	// Open lists the names of breakers currently open or half-open.
+	Open []string `json:"open,omitempty"`
+}
+
+// HealthRule derives a status from the registry's breakers; Health reports
+// the worst status any rule returns.
+type HealthRule func(breakers []*Breaker) HealthStatus
+
+// AnyOpenDegraded is the default rule: the registry is degraded while any
+// breaker is open, and healthy otherwise.
+func AnyOpenDegraded() HealthRule {
+	return func(breakers []*Breaker) HealthStatus {
+		for _, b := range breakers {
+			if b.State() == StateOpen {
+				return Degraded
+			}
+		}
+		return Healthy
+	}
+}
+
+// CriticalOpen marks the registry unhealthy while any of the named critical
+// breakers is open — e.g. the primary database, without which the service
+// cannot do useful work.
+func CriticalOpen(names ...string) HealthRule {
+	critical := make(map[string]bool, len(names))
+	for _, name := range names {
+		critical[name] = true
+	}
+	return func(breakers []*Breaker) HealthStatus {
+		for _, b := range breakers {
+			if critical[b.Name()] && b.State() == StateOpen {
+				return Unhealthy
+			}
+		}
+		return Healthy
+	}
+}
+
+// OpenFractionUnhealthy marks the registry unhealthy once more than the
+// given fraction of its breakers are open.
+func OpenFractionUnhealthy(fraction float64) HealthRule {
+	return func(breakers []*Breaker) HealthStatus {
+		if len(breakers) == 0 {
+			return Healthy
+		}
+		open := 0
+		for _, b := range breakers {
+			if b.State() == StateOpen {
+				open++
+			}
+		}
+		if float64(open)/float64(len(breakers)) > fraction {
+			return Unhealthy
+		}
+		return Healthy
+	}
+}
+
+// SetHealthRules replaces the rules consulted by Health. Without rules the
+// registry uses AnyOpenDegraded.
+func (r *Registry) SetHealthRules(rules ...HealthRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.healthRules = rules
+}
+
+// Health evaluates the health rules against the registry's breakers and
+// returns the worst status along with the open breakers.
+func (r *Registry) Health() HealthReport {
+	r.mu.RLock()
+	rules := r.healthRules
+	r.mu.RUnlock()
+	if len(rules) == 0 {
+		rules = []HealthRule{AnyOpenDegraded()}
+	}
+
+	breakers := r.All()
+	report := HealthReport{Status: Healthy}
+	for _, rule := range rules {
+		if status := rule(breakers); status > report.Status {
+			report.Status = status
+		}
+	}
+	for _, b := range breakers {
+		if state := b.State(); state == StateOpen || state == StateHalfOpen {
+			report.Open = append(report.Open, b.Name())
+		}
+	}
+	return report
+}
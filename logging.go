@@ -0,0 +1,70 @@
+package circuitbreaker
+
+import (
+	"log/slog"
+	"time"
+)
+
+// SlogEvents logs breaker events with log/slog and structured attributes.
+// Attach it to a registry for registry-wide logging, or use it as a
+// breaker's Metrics collector (typically combined with a real metrics
+// backend via MultiCollector) to log rejections too:
+//
+//	events := circuitbreaker.NewSlogEvents(slog.Default())
+//	events.Attach(registry)
+type SlogEvents struct {
+	logger *slog.Logger
+}
+
+// NewSlogEvents returns an event logger writing to logger.
+func NewSlogEvents(logger *slog.Logger) *SlogEvents {
+	return &SlogEvents{logger: logger}
+}
+
+// Attach registers the event logger for all state changes on r. Trips are
+// logged with the counts that caused them.
+func (e *SlogEvents) Attach(r *Registry) {
+	r.OnStateChange(func(name string, from, to State) {
+		attrs := []any{
+			slog.String("breaker", name),
+			slog.String("from", from.String()),
+			slog.String("to", to.String()),
+		}
+		if to == StateOpen {
+			if b, ok := r.Lookup(name); ok {
+				counts := b.TripCounts()
+				attrs = append(attrs,
+					slog.Uint64("requests", counts.Requests),
+					slog.Uint64("failures", counts.TotalFailures),
+					slog.Uint64("consecutive_failures", counts.ConsecutiveFailures),
+				)
+			}
+			e.logger.Warn("circuit breaker opened", attrs...)
+			return
+		}
+		e.logger.Info("circuit breaker state changed", attrs...)
+	})
+}
+
+// RecordSuccess implements MetricsCollector; successes are not logged.
+func (e *SlogEvents) RecordSuccess(string, time.Duration) {}
+
+// RecordFailure implements MetricsCollector; individual failures are not
+// logged — the trip that results from them is.
+func (e *SlogEvents) RecordFailure(string, time.Duration) {}
+
+// RecordRejection implements MetricsCollector.
+func (e *SlogEvents) RecordRejection(name string) {
+	e.logger.Debug("circuit breaker rejected request", slog.String("breaker", name))
+}
+
+// RecordStateChange implements MetricsCollector, for breakers that are not
+// managed by a registry. Use either Attach or the Metrics hook for a given
+// breaker, not both, or transitions are logged twice.
+func (e *SlogEvents) RecordStateChange(name string, from, to State) {
+	e.logger.Info("circuit breaker state changed",
+		slog.String("breaker", name),
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+	)
+}
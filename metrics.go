@@ -0,0 +1,201 @@
+package circuitbreaker
+
+import (
+	"context"
+	"time"
+)
+
+// MetricsCollector receives breaker events for export to a metrics system.
+// The core deliberately depends only on this interface; backends live in
+// their own packages (package prom for Prometheus) so users on other stacks
+// don't drag in client_golang. Implementations must be safe for concurrent
+// use and should be cheap: collectors are invoked on the request path.
+type MetricsCollector interface {
+	// RecordSuccess is called after a request succeeds, with the time the
+	// request took.
+	RecordSuccess(name string, duration time.Duration)
+	// RecordFailure is called after a request fails, with the time the
+	// request took.
+	RecordFailure(name string, duration time.Duration)
+	// RecordRejection is called when a request is rejected without being
+	// attempted because the breaker is open or out of half-open probes.
+	RecordRejection(name string)
+	// RecordStateChange is called when the breaker changes state.
+	RecordStateChange(name string, from, to State)
+}
+
+// ProbeRecorder is an optional interface a MetricsCollector may implement
+// to receive half-open probe events. Probe counters distinguish a breaker
+// that flaps because its probes keep failing from one that never sees
+// probe traffic at all.
+type ProbeRecorder interface {
+	// RecordProbeAttempt is called when a request is admitted while the
+	// breaker is half-open.
+	RecordProbeAttempt(name string)
+	// RecordProbeResult is called with the outcome of such a request.
+	RecordProbeResult(name string, success bool)
+}
+
+// ContextRecorder is an optional interface a MetricsCollector may
+// implement to receive the request context along with each outcome, e.g.
+// to attach trace exemplars to latency histograms. It is used instead of
+// RecordSuccess/RecordFailure for requests that went through DoContext or
+// AllowContext.
+type ContextRecorder interface {
+	RecordSuccessContext(ctx context.Context, name string, duration time.Duration)
+	RecordFailureContext(ctx context.Context, name string, duration time.Duration)
+}
+
+// Rejection reasons distinguish load shed because of an outage from load
+// shed by configured limits. The breaker itself reports ReasonOpen and
+// ReasonTooManyRequests; middleware reports ReasonBulkheadFull and
+// ReasonRateLimited for its own admission decisions.
+const (
+	ReasonOpen             = "open"
+	ReasonTooManyRequests  = "too_many_requests"
+	ReasonBulkheadFull     = "bulkhead_full"
+	ReasonRateLimited      = "rate_limited"
+	ReasonLowPriority      = "low_priority"
+	ReasonDeadlineTooShort = "deadline_too_short"
+)
+
+// RejectionReasonRecorder is an optional interface a MetricsCollector may
+// implement to receive the reason a request was rejected. When a collector
+// implements it, the breaker calls RecordRejectionReason instead of
+// RecordRejection.
+type RejectionReasonRecorder interface {
+	RecordRejectionReason(name, reason string)
+}
+
+// recordRejection reports a rejection to the collector with the reason if
+// it cares about reasons.
+func recordRejection(collector MetricsCollector, name, reason string) {
+	if collector == nil {
+		return
+	}
+	if rr, ok := collector.(RejectionReasonRecorder); ok {
+		rr.RecordRejectionReason(name, reason)
+		return
+	}
+	collector.RecordRejection(name)
+}
+
+// ReportRejection records a load-shedding decision made on the breaker's
+// behalf — a bulkhead or rate limit in middleware rejecting a request the
+// breaker itself would have admitted — in the breaker's rejection count
+// and metrics collector, under reasons like ReasonBulkheadFull.
+func (b *Breaker) ReportRejection(reason string) {
+	b.mu.Lock()
+	b.rejections++
+	b.mu.Unlock()
+	recordRejection(b.cfg.Metrics, b.cfg.Name, reason)
+}
+
+// NopMetrics is a MetricsCollector that discards all events.
+type NopMetrics struct{}
+
+// RecordSuccess implements MetricsCollector.
+func (NopMetrics) RecordSuccess(string, time.Duration) {}
+
+// RecordFailure implements MetricsCollector.
+func (NopMetrics) RecordFailure(string, time.Duration) {}
+
+// RecordRejection implements MetricsCollector.
+func (NopMetrics) RecordRejection(string) {}
+
+// RecordStateChange implements MetricsCollector.
+func (NopMetrics) RecordStateChange(string, State, State) {}
+
+// MultiCollector returns a MetricsCollector fanning every event out to
+// each of collectors in order, so e.g. a metrics backend and an event
+// logger can share a breaker's Metrics hook.
+func MultiCollector(collectors ...MetricsCollector) MetricsCollector {
+	return multiCollector(collectors)
+}
+
+type multiCollector []MetricsCollector
+
+func (m multiCollector) RecordSuccess(name string, duration time.Duration) {
+	for _, c := range m {
+		c.RecordSuccess(name, duration)
+	}
+}
+
+func (m multiCollector) RecordFailure(name string, duration time.Duration) {
+	for _, c := range m {
+		c.RecordFailure(name, duration)
+	}
+}
+
+func (m multiCollector) RecordRejection(name string) {
+	for _, c := range m {
+		c.RecordRejection(name)
+	}
+}
+
+func (m multiCollector) RecordStateChange(name string, from, to State) {
+	for _, c := range m {
+		c.RecordStateChange(name, from, to)
+	}
+}
+
+// RecordProbeAttempt forwards to the collectors implementing
+// ProbeRecorder.
+func (m multiCollector) RecordProbeAttempt(name string) {
+	for _, c := range m {
+		if pr, ok := c.(ProbeRecorder); ok {
+			pr.RecordProbeAttempt(name)
+		}
+	}
+}
+
+// RecordProbeResult forwards to the collectors implementing ProbeRecorder.
+func (m multiCollector) RecordProbeResult(name string, success bool) {
+	for _, c := range m {
+		if pr, ok := c.(ProbeRecorder); ok {
+			pr.RecordProbeResult(name, success)
+		}
+	}
+}
+
+// RecordRejectionReason forwards the reason to collectors that understand
+// it and falls back to RecordRejection for those that don't.
+func (m multiCollector) RecordRejectionReason(name, reason string) {
+	for _, c := range m {
+		recordRejection(c, name, reason)
+	}
+}
+
+// RecordFailureCategory forwards to the collectors implementing
+// FailureCategoryRecorder.
+func (m multiCollector) RecordFailureCategory(name, category string) {
+	for _, c := range m {
+		if fc, ok := c.(FailureCategoryRecorder); ok {
+			fc.RecordFailureCategory(name, category)
+		}
+	}
+}
+
+// RecordSuccessContext forwards the context to collectors that understand
+// it and falls back to RecordSuccess for those that don't.
+func (m multiCollector) RecordSuccessContext(ctx context.Context, name string, duration time.Duration) {
+	for _, c := range m {
+		if cr, ok := c.(ContextRecorder); ok {
+			cr.RecordSuccessContext(ctx, name, duration)
+		} else {
+			c.RecordSuccess(name, duration)
+		}
+	}
+}
+
+// RecordFailureContext forwards the context to collectors that understand
+// it and falls back to RecordFailure for those that don't.
+func (m multiCollector) RecordFailureContext(ctx context.Context, name string, duration time.Duration) {
+	for _, c := range m {
+		if cr, ok := c.(ContextRecorder); ok {
+			cr.RecordFailureContext(ctx, name, duration)
+		} else {
+			c.RecordFailure(name, duration)
+		}
+	}
+}
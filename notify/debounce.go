@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+// debouncer suppresses repeat notifications for the same breaker within a
+// window, so a flapping breaker doesn't page fifty times.
+type debouncer struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func newDebouncer(window time.Duration) *debouncer {
+	return &debouncer{window: window, last: make(map[string]time.Time)}
+}
+
+// allow reports whether a notification for the breaker may be sent now,
+// and records it if so.
+func (d *debouncer) allow(breaker string, now time.Time) bool {
+	if d.window <= 0 {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if last, ok := d.last[breaker]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	d.last[breaker] = now
+	return true
+}
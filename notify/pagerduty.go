@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty raises and resolves incidents through the PagerDuty Events API
+// v2. A breaker opening triggers an alert keyed by the breaker name, so
+// repeat openings deduplicate into one incident; a breaker closing
+// resolves it. Triggers are additionally debounced per breaker (default 5
+// minutes).
+type PagerDuty struct {
+	routingKey string
+	source     string
+	endpoint   string
+	client     *http.Client
+	debounce   *debouncer
+
+	// events is never closed — see the field comment on Webhook; Stop
+	// signals through quit instead.
+	events   chan Event
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// OnError, if set, is called when a delivery fails.
+	OnError func(err error)
+}
+
+// PagerDutyOption configures a PagerDuty notifier.
+type PagerDutyOption func(*PagerDuty)
+
+// WithPagerDutyDebounce sets the per-breaker debounce window for triggers.
+// Zero disables debouncing.
+func WithPagerDutyDebounce(window time.Duration) PagerDutyOption {
+	return func(p *PagerDuty) { p.debounce = newDebouncer(window) }
+}
+
+// WithPagerDutySource sets the "source" field reported on alerts,
+// typically the service or host name.
+func WithPagerDutySource(source string) PagerDutyOption {
+	return func(p *PagerDuty) { p.source = source }
+}
+
+// WithPagerDutyEndpoint overrides the Events API endpoint, for tests.
+func WithPagerDutyEndpoint(url string) PagerDutyOption {
+	return func(p *PagerDuty) { p.endpoint = url }
+}
+
+// NewPagerDuty returns a PagerDuty notifier using the integration's
+// routing key.
+func NewPagerDuty(routingKey string, opts ...PagerDutyOption) *PagerDuty {
+	p := &PagerDuty{
+		routingKey: routingKey,
+		source:     "circuit-breaker",
+		endpoint:   pagerDutyEventsURL,
+		client:     &http.Client{Timeout: 5 * time.Second},
+		debounce:   newDebouncer(5 * time.Minute),
+		events:     make(chan Event, 64),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	go p.deliverLoop()
+	return p
+}
+
+// Attach subscribes the notifier to registry's state changes and registers
+// its Stop with the registry's shutdown hooks.
+func (p *PagerDuty) Attach(registry *circuitbreaker.Registry) {
+	registry.OnStateChange(func(name string, from, to circuitbreaker.State) {
+		select {
+		case p.events <- Event{Breaker: name, From: from.String(), To: to.String(), At: time.Now()}:
+		default:
+		}
+	})
+	registry.AddShutdownHook(func(context.Context) error {
+		p.Stop()
+		return nil
+	})
+}
+
+// Stop drains queued events and stops the delivery goroutine. It is
+// idempotent, and events arriving after Stop are dropped.
+func (p *PagerDuty) Stop() {
+	p.stopOnce.Do(func() { close(p.quit) })
+	<-p.done
+}
+
+func (p *PagerDuty) deliverLoop() {
+	defer close(p.done)
+	for {
+		select {
+		case event := <-p.events:
+			p.handle(event)
+		case <-p.quit:
+			for {
+				select {
+				case event := <-p.events:
+					p.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *PagerDuty) handle(event Event) {
+	var err error
+	switch event.To {
+	case circuitbreaker.StateOpen.String():
+		if !p.debounce.allow(event.Breaker, event.At) {
+			return
+		}
+		err = p.send("trigger", event)
+	case circuitbreaker.StateClosed.String():
+		err = p.send("resolve", event)
+	default:
+		return // half-open is not actionable
+	}
+	if err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+func (p *PagerDuty) send(action string, event Event) error {
+	payload, err := json.Marshal(map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": action,
+		"dedup_key":    "circuitbreaker-" + event.Breaker,
+		"payload": map[string]any{
+			"summary":   fmt.Sprintf("circuit breaker %s: %s → %s", event.Breaker, event.From, event.To),
+			"source":    p.source,
+			"severity":  "error",
+			"timestamp": event.At.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Post(p.endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: pagerduty returned %s", resp.Status)
+	}
+	return nil
+}
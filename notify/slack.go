@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Slack posts breaker state changes to a Slack incoming webhook. Openings
+// are debounced per breaker (default 5 minutes) so a flapping breaker
+// produces one message, not a flood; closings always go through so the
+// all-clear is never suppressed.
+type Slack struct {
+	webhookURL string
+	client     *http.Client
+	debounce   *debouncer
+
+	// events is never closed — see the field comment on Webhook; Stop
+	// signals through quit instead.
+	events   chan Event
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// OnError, if set, is called when a delivery fails.
+	OnError func(err error)
+}
+
+// SlackOption configures a Slack notifier.
+type SlackOption func(*Slack)
+
+// WithSlackDebounce sets the per-breaker debounce window for opening
+// notifications. Zero disables debouncing.
+func WithSlackDebounce(window time.Duration) SlackOption {
+	return func(s *Slack) { s.debounce = newDebouncer(window) }
+}
+
+// WithSlackHTTPClient sets the http.Client used for deliveries.
+func WithSlackHTTPClient(client *http.Client) SlackOption {
+	return func(s *Slack) { s.client = client }
+}
+
+// NewSlack returns a Slack notifier posting to webhookURL.
+func NewSlack(webhookURL string, opts ...SlackOption) *Slack {
+	s := &Slack{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 5 * time.Second},
+		debounce:   newDebouncer(5 * time.Minute),
+		events:     make(chan Event, 64),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.deliverLoop()
+	return s
+}
+
+// Attach subscribes the notifier to registry's state changes and registers
+// its Stop with the registry's shutdown hooks.
+func (s *Slack) Attach(registry *circuitbreaker.Registry) {
+	registry.OnStateChange(func(name string, from, to circuitbreaker.State) {
+		select {
+		case s.events <- Event{Breaker: name, From: from.String(), To: to.String(), At: time.Now()}:
+		default:
+		}
+	})
+	registry.AddShutdownHook(func(context.Context) error {
+		s.Stop()
+		return nil
+	})
+}
+
+// Stop drains queued events and stops the delivery goroutine. It is
+// idempotent, and events arriving after Stop are dropped.
+func (s *Slack) Stop() {
+	s.stopOnce.Do(func() { close(s.quit) })
+	<-s.done
+}
+
+func (s *Slack) deliverLoop() {
+	defer close(s.done)
+	for {
+		select {
+		case event := <-s.events:
+			s.handle(event)
+		case <-s.quit:
+			for {
+				select {
+				case event := <-s.events:
+					s.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Slack) handle(event Event) {
+	if event.To == circuitbreaker.StateOpen.String() &&
+		!s.debounce.allow(event.Breaker, event.At) {
+		return
+	}
+	if err := s.post(event); err != nil && s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+func (s *Slack) post(event Event) error {
+	icon := ":large_green_circle:"
+	if event.To == circuitbreaker.StateOpen.String() {
+		icon = ":red_circle:"
+	} else if event.To == circuitbreaker.StateHalfOpen.String() {
+		icon = ":large_yellow_circle:"
+	}
+	payload, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("%s circuit breaker *%s*: %s → %s",
+			icon, event.Breaker, event.From, event.To),
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: slack returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlackPostsAndDebounces(t *testing.T) {
+	posts := make(chan string, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		posts <- payload["text"]
+	}))
+	defer server.Close()
+
+	s := NewSlack(server.URL, WithSlackDebounce(time.Hour))
+	defer s.Stop()
+	registry := tripRegistry()
+	s.Attach(registry)
+
+	trip(registry, "db")
+	select {
+	case text := <-posts:
+		if !strings.Contains(text, "*db*") || !strings.Contains(text, "open") {
+			t.Fatalf("message = %q", text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no slack post within 2s")
+	}
+
+	// A second opening inside the debounce window is suppressed; the
+	// close that precedes it (via Reset) still goes through.
+	registry.Get("db").Reset()
+	trip(registry, "db")
+	s.Stop() // drain
+	var texts []string
+	for len(posts) > 0 {
+		texts = append(texts, <-posts)
+	}
+	for _, text := range texts {
+		if strings.Contains(text, "→ open") {
+			t.Fatalf("debounced opening was posted: %q", text)
+		}
+	}
+}
+
+func TestSlackTransitionAfterStop(t *testing.T) {
+	s := NewSlack("http://127.0.0.1:0")
+	registry := tripRegistry()
+	s.Attach(registry)
+	s.Stop()
+	trip(registry, "db") // must not panic
+}
+
+func TestPagerDutyTriggerAndResolve(t *testing.T) {
+	actions := make(chan string, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		actions <- payload["event_action"].(string)
+	}))
+	defer server.Close()
+
+	p := NewPagerDuty("key", WithPagerDutyEndpoint(server.URL))
+	registry := tripRegistry()
+	p.Attach(registry)
+
+	trip(registry, "db")
+	registry.Get("db").Reset()
+	p.Stop()
+
+	var got []string
+	for len(actions) > 0 {
+		got = append(got, <-actions)
+	}
+	if len(got) != 2 || got[0] != "trigger" || got[1] != "resolve" {
+		t.Fatalf("actions = %v, want [trigger resolve]", got)
+	}
+}
+
+func TestPagerDutyTransitionAfterStop(t *testing.T) {
+	p := NewPagerDuty("key", WithPagerDutyEndpoint("http://127.0.0.1:0"))
+	registry := tripRegistry()
+	p.Attach(registry)
+	p.Stop()
+	trip(registry, "db") // must not panic
+}
@@ -0,0 +1,178 @@
+// Package notify pushes breaker state changes to external systems —
+// generic webhooks, Slack, PagerDuty — so runbooks, ticketing and paging
+// can react to circuits opening without polling metrics.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Event is the JSON payload delivered for each state change.
+type Event struct {
+	Breaker string    `json:"breaker"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	At      time.Time `json:"at"`
+}
+
+// Webhook POSTs an Event to each configured URL when a breaker changes
+// state. Deliveries happen on a background goroutine with retry, and the
+// notifier guards each endpoint with an internal circuit breaker of its
+// own, so a dead webhook endpoint can neither block request handling nor
+// be hammered during its outage.
+type Webhook struct {
+	urls     []string
+	client   *http.Client
+	retries  int
+	backoff  time.Duration
+	breakers *circuitbreaker.Registry
+
+	// events is never closed: the state-change listener registered by
+	// Attach cannot be unregistered and keeps sending after Stop, which
+	// is safe against an open channel but would panic against a closed
+	// one. Stop signals through quit instead; late events are simply
+	// dropped by the listener's non-blocking send.
+	events   chan Event
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// OnError, if set, is called when a delivery ultimately fails.
+	OnError func(url string, err error)
+}
+
+// WebhookOption configures a Webhook.
+type WebhookOption func(*Webhook)
+
+// WithHTTPClient sets the http.Client used for deliveries. The default
+// client has a 5s timeout.
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) { w.client = client }
+}
+
+// WithRetries sets how many delivery attempts are made per event and the
+// initial backoff between them (doubling each attempt). The default is 3
+// attempts starting at 500ms.
+func WithRetries(retries int, backoff time.Duration) WebhookOption {
+	return func(w *Webhook) {
+		w.retries = retries
+		w.backoff = backoff
+	}
+}
+
+// NewWebhook returns a Webhook delivering to urls. Call Attach to start
+// receiving events; Stop (or the registry's Shutdown, which Attach hooks
+// into) drains the queue.
+func NewWebhook(urls []string, opts ...WebhookOption) *Webhook {
+	w := &Webhook{
+		urls:    urls,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		retries: 3,
+		backoff: 500 * time.Millisecond,
+		events:  make(chan Event, 64),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.breakers = circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+	})
+	go w.deliverLoop()
+	return w
+}
+
+// Attach subscribes the notifier to registry's state changes and registers
+// its Stop with the registry's shutdown hooks.
+func (w *Webhook) Attach(registry *circuitbreaker.Registry) {
+	registry.OnStateChange(func(name string, from, to circuitbreaker.State) {
+		event := Event{Breaker: name, From: from.String(), To: to.String(), At: time.Now()}
+		select {
+		case w.events <- event:
+		default: // never block the breaker on a slow webhook
+			if w.OnError != nil {
+				w.OnError("", fmt.Errorf("notify: event queue full, dropping %s", name))
+			}
+		}
+	})
+	registry.AddShutdownHook(func(context.Context) error {
+		w.Stop()
+		return nil
+	})
+}
+
+// Stop drains queued events and stops the delivery goroutine. It is
+// idempotent, and events arriving after Stop are dropped.
+func (w *Webhook) Stop() {
+	w.stopOnce.Do(func() { close(w.quit) })
+	<-w.done
+}
+
+func (w *Webhook) deliverLoop() {
+	defer close(w.done)
+	for {
+		select {
+		case event := <-w.events:
+			w.handle(event)
+		case <-w.quit:
+			for {
+				select {
+				case event := <-w.events:
+					w.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *Webhook) handle(event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	for _, url := range w.urls {
+		w.deliver(url, payload)
+	}
+}
+
+func (w *Webhook) deliver(url string, payload []byte) {
+	b := w.breakers.Get(url)
+	backoff := w.backoff
+	var err error
+	for attempt := 0; attempt < w.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		err = b.Do(func() error { return w.post(url, payload) })
+		if err == nil {
+			return
+		}
+	}
+	if w.OnError != nil {
+		w.OnError(url, err)
+	}
+}
+
+func (w *Webhook) post(url string, payload []byte) error {
+	resp, err := w.client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook %s returned %s", url, resp.Status)
+	}
+	return nil
+}
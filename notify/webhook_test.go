@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func tripRegistry() *circuitbreaker.Registry {
+	return circuitbreaker.NewRegistry(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+}
+
+func trip(registry *circuitbreaker.Registry, name string) {
+	registry.Get(name).Do(func() error { return errBoom })
+}
+
+var errBoom = &timeoutError{}
+
+type timeoutError struct{}
+
+func (*timeoutError) Error() string { return "boom" }
+
+func TestWebhookDeliversStateChanges(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		received <- event
+	}))
+	defer server.Close()
+
+	w := NewWebhook([]string{server.URL})
+	defer w.Stop()
+	registry := tripRegistry()
+	w.Attach(registry)
+	trip(registry, "db")
+
+	select {
+	case event := <-received:
+		if event.Breaker != "db" || event.From != "closed" || event.To != "open" {
+			t.Fatalf("event = %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no delivery within 2s")
+	}
+}
+
+func TestWebhookStopDrainsQueue(t *testing.T) {
+	received := make(chan struct{}, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer server.Close()
+
+	w := NewWebhook([]string{server.URL})
+	registry := tripRegistry()
+	w.Attach(registry)
+	trip(registry, "db")
+	w.Stop()
+	if len(received) == 0 {
+		t.Fatal("Stop returned before the queued event was delivered")
+	}
+}
+
+// Regression: the state-change listener cannot be unregistered, so a
+// transition after Stop used to panic with "send on closed channel".
+func TestWebhookTransitionAfterStop(t *testing.T) {
+	w := NewWebhook([]string{"http://127.0.0.1:0"})
+	registry := tripRegistry()
+	w.Attach(registry)
+	w.Stop()
+	w.Stop() // and Stop is idempotent
+	trip(registry, "db")
+}
@@ -0,0 +1,119 @@
+package circuitbreaker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Envoy's outlier detection defaults, mirrored here so an in-process
+// policy behaves like the mesh around it.
+const (
+	defaultConsecutive5xx     = 5
+	defaultBaseEjectionTime   = 30 * time.Second
+	defaultMaxEjectionTime    = 300 * time.Second
+	defaultMaxEjectionPercent = 10
+)
+
+// OutlierPolicy is an Envoy-outlier-detection-style strategy over a fleet
+// of per-backend breakers: a backend is ejected (its circuit opened)
+// after a run of consecutive failures, ejections are capped at a
+// percentage of the fleet so a poisoned request pattern can't eject every
+// backend at once, and repeat offenders stay ejected longer. Services
+// running behind an xDS mesh can hand the resulting Registry to the
+// per-host/per-peer integrations (cbhttp, cbgrpc) and get in-process
+// behavior that mirrors the mesh's outlier detection.
+//
+// What counts as a "5xx" is the integration's classification: the HTTP
+// and gRPC integrations already record 5xx statuses and server-fault
+// codes as failures.
+type OutlierPolicy struct {
+	// Consecutive5xx is the run of consecutive failures ejecting a
+	// backend. Defaults to 5.
+	Consecutive5xx uint64
+	// BaseEjectionTime is the first ejection's duration; a backend's
+	// n-th ejection lasts n times this, capped by MaxEjectionTime.
+	// Defaults to 30s.
+	BaseEjectionTime time.Duration
+	// MaxEjectionTime caps the grown ejection time. Defaults to 300s.
+	MaxEjectionTime time.Duration
+	// MaxEjectionPercent caps how much of the fleet may be ejected at
+	// once; at least one backend can always be ejected. Defaults to 10.
+	MaxEjectionPercent int
+}
+
+func (p OutlierPolicy) withDefaults() OutlierPolicy {
+	if p.Consecutive5xx == 0 {
+		p.Consecutive5xx = defaultConsecutive5xx
+	}
+	if p.BaseEjectionTime <= 0 {
+		p.BaseEjectionTime = defaultBaseEjectionTime
+	}
+	if p.MaxEjectionTime <= 0 {
+		p.MaxEjectionTime = defaultMaxEjectionTime
+	}
+	if p.MaxEjectionPercent <= 0 {
+		p.MaxEjectionPercent = defaultMaxEjectionPercent
+	}
+	return p
+}
+
+// Registry returns a Registry whose breakers — one per backend, created
+// on demand by the integrations — implement the policy. base provides
+// everything else about the breakers (metrics, classification, history);
+// its OpenTimeout, ReadyToTrip and OnStateChange are overridden by the
+// policy, with base's OnStateChange still invoked after the policy's.
+func (p OutlierPolicy) Registry(base Config) *Registry {
+	p = p.withDefaults()
+	// ReadyToTrip runs under the evaluated breaker's own lock, so the
+	// ejected share of the fleet is tracked with a counter maintained
+	// from state-change notifications instead of polling every breaker's
+	// state.
+	var registry *Registry
+	var ejected atomic.Int64
+	onStateChange := base.OnStateChange
+	base.OpenTimeout = p.BaseEjectionTime
+	base.ReadyToTrip = func(counts Counts) bool {
+		if counts.ConsecutiveFailures < p.Consecutive5xx {
+			return false
+		}
+		open := ejected.Load()
+		if open == 0 {
+			// At least one backend can always be ejected.
+			return true
+		}
+		total := int64(len(registry.All()))
+		return (open+1)*100 <= int64(p.MaxEjectionPercent)*total
+	}
+	base.OnStateChange = func(name string, from, to State) {
+		if to == StateOpen {
+			ejected.Add(1)
+			p.growEjection(registry, name)
+		}
+		if from == StateOpen {
+			ejected.Add(-1)
+		}
+		if onStateChange != nil {
+			onStateChange(name, from, to)
+		}
+	}
+	registry = NewRegistry(base)
+	return registry
+}
+
+// growEjection extends a repeat offender's ejection to its ejection count
+// times the base time, capped by MaxEjectionTime.
+func (p OutlierPolicy) growEjection(registry *Registry, name string) {
+	b, ok := registry.Lookup(name)
+	if !ok {
+		return
+	}
+	opens := b.Opens()
+	if opens <= 1 {
+		return
+	}
+	ejection := time.Duration(opens) * p.BaseEjectionTime
+	if ejection > p.MaxEjectionTime {
+		ejection = p.MaxEjectionTime
+	}
+	b.HoldOpen(ejection)
+}
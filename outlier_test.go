@@ -0,0 +1,78 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+)
+
+func eject(b *Breaker, failures int) {
+	for i := 0; i < failures; i++ {
+		b.Do(func() error { return errFail })
+	}
+}
+
+func TestOutlierPolicyEjectsAfterConsecutiveFailures(t *testing.T) {
+	registry := OutlierPolicy{Consecutive5xx: 3}.Registry(Config{})
+	b := registry.Get("backend-1")
+	eject(b, 2)
+	if got := b.State(); got != StateClosed {
+		t.Fatalf("state after 2 failures = %v, want closed", got)
+	}
+	eject(b, 1)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state after 3 failures = %v, want open (ejected)", got)
+	}
+}
+
+func TestOutlierPolicyMaxEjectionPercent(t *testing.T) {
+	registry := OutlierPolicy{
+		Consecutive5xx:     1,
+		MaxEjectionPercent: 25,
+	}.Registry(Config{})
+	for _, name := range []string{"a", "b", "c", "d"} {
+		registry.Get(name)
+	}
+
+	// 25% of four backends: the first ejection fits the cap...
+	eject(registry.Get("a"), 1)
+	if got := registry.Get("a").State(); got != StateOpen {
+		t.Fatalf("first backend = %v, want open", got)
+	}
+	// ...the second would exceed it and is refused.
+	eject(registry.Get("b"), 5)
+	if got := registry.Get("b").State(); got != StateClosed {
+		t.Fatalf("second backend = %v, want closed (ejection cap)", got)
+	}
+}
+
+func TestOutlierPolicyAlwaysAllowsOneEjection(t *testing.T) {
+	// With one backend, any percentage cap would forbid ejection
+	// entirely; the policy guarantees at least one.
+	registry := OutlierPolicy{Consecutive5xx: 1, MaxEjectionPercent: 10}.Registry(Config{})
+	b := registry.Get("only")
+	eject(b, 1)
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+}
+
+func TestOutlierPolicyGrowsRepeatEjections(t *testing.T) {
+	registry := OutlierPolicy{
+		Consecutive5xx:   1,
+		BaseEjectionTime: 20 * time.Millisecond,
+		MaxEjectionTime:  time.Minute,
+	}.Registry(Config{})
+	b := registry.Get("flaky")
+
+	eject(b, 1)
+	first := b.UntilHalfOpen()
+	time.Sleep(25 * time.Millisecond)
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("state after base ejection = %v, want half-open", got)
+	}
+	eject(b, 1) // failed probe: second ejection
+	second := b.UntilHalfOpen()
+	if second <= first {
+		t.Fatalf("second ejection (%v) not longer than first (%v)", second, first)
+	}
+}
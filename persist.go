@@ -0,0 +1,177 @@
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Snapshot captures a breaker's state for persistence, so a restarting
+// process can pick up where it left off instead of rediscovering an outage.
+type Snapshot struct {
+	Name   string    `json:"name"`
+	State  State     `json:"state"`
+	Counts Counts    `json:"counts"`
+	Expiry time.Time `json:"expiry"`
+	Taken  time.Time `json:"taken"`
+}
+
+// Snapshot returns the breaker's current state as a Snapshot.
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Snapshot{
+		Name:   b.cfg.Name,
+		State:  b.state,
+		Counts: b.counts,
+		Expiry: b.expiry,
+		Taken:  time.Now(),
+	}
+}
+
+// Restore sets the breaker's state from a Snapshot. A restored open state
+// whose expiry has already passed becomes half-open on the next request, as
+// usual. Restore does not fire OnStateChange.
+func (b *Breaker) Restore(s Snapshot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = s.State
+	b.counts = s.Counts
+	b.expiry = s.Expiry
+	b.generation++
+}
+
+// SnapshotStore persists breaker snapshots between process restarts.
+type SnapshotStore interface {
+	Save(snapshots []Snapshot) error
+	Load() ([]Snapshot, error)
+}
+
+// FileSnapshotStore is a SnapshotStore backed by a single JSON file.
+// Writes go through a temporary file and rename, so a crash mid-save never
+// leaves a truncated snapshot behind.
+type FileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore returns a store writing to path.
+func NewFileSnapshotStore(path string) *FileSnapshotStore {
+	return &FileSnapshotStore{path: path}
+}
+
+// Save implements SnapshotStore.
+func (s *FileSnapshotStore) Save(snapshots []Snapshot) error {
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Load implements SnapshotStore. A missing file is not an error; it returns
+// no snapshots.
+func (s *FileSnapshotStore) Load() ([]Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snapshots []Snapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// Persister periodically saves every breaker in a Registry to a
+// SnapshotStore and restores them on startup.
+type Persister struct {
+	registry *Registry
+	store    SnapshotStore
+	interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPersister returns a Persister saving registry's breakers to store
+// every interval. Call Restore once at startup, then Start; call Stop
+// during shutdown to write a final snapshot.
+func NewPersister(registry *Registry, store SnapshotStore, interval time.Duration) *Persister {
+	return &Persister{registry: registry, store: store, interval: interval}
+}
+
+// Restore loads stored snapshots and applies each to the breaker of the
+// same name, creating it from the registry defaults if needed.
+func (p *Persister) Restore() error {
+	snapshots, err := p.store.Load()
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		p.registry.Get(s.Name).Restore(s)
+	}
+	return nil
+}
+
+// Start begins the periodic save loop in a background goroutine.
+func (p *Persister) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done)
+}
+
+// Stop ends the save loop and writes a final snapshot.
+func (p *Persister) Stop() error {
+	p.mu.Lock()
+	if p.stop == nil {
+		p.mu.Unlock()
+		return p.save()
+	}
+	close(p.stop)
+	done := p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+	<-done
+	return p.save()
+}
+
+func (p *Persister) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = p.save()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *Persister) save() error {
+	breakers := p.registry.All()
+	snapshots := make([]Snapshot, 0, len(breakers))
+	for _, b := range breakers {
+		snapshots = append(snapshots, b.Snapshot())
+	}
+	return p.store.Save(snapshots)
+}
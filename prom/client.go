@@ -0,0 +1,123 @@
+package prom
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultMaxHosts = 50
+
+// ClientMetrics exports per-host, per-method telemetry for the cbhttp
+// client (it implements cbhttp.RequestObserver):
+//
+//	<ns>_client_requests_total{host, method, code}
+//	<ns>_client_request_duration_seconds{host, method}
+//
+// The host label is cardinality-guarded: after maxHosts distinct hosts,
+// further hosts are reported as "other". Methods outside the standard set
+// and status codes are bucketed the same way ("other", "xxx").
+type ClientMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	maxHosts int
+
+	mu    sync.Mutex
+	hosts map[string]struct{}
+}
+
+// ClientMetricsOption configures ClientMetrics construction.
+type ClientMetricsOption func(*clientMetricsOptions)
+
+type clientMetricsOptions struct {
+	buckets  []float64
+	maxHosts int
+}
+
+// WithClientBuckets sets the latency histogram buckets, in seconds.
+func WithClientBuckets(buckets []float64) ClientMetricsOption {
+	return func(o *clientMetricsOptions) { o.buckets = buckets }
+}
+
+// WithMaxHosts sets how many distinct host label values are exported
+// before further hosts aggregate into "other". Defaults to 50.
+func WithMaxHosts(n int) ClientMetricsOption {
+	return func(o *clientMetricsOptions) { o.maxHosts = n }
+}
+
+// NewClientMetrics returns a ClientMetrics registering its collectors
+// with the default Prometheus registry under the given namespace.
+func NewClientMetrics(namespace string, opts ...ClientMetricsOption) *ClientMetrics {
+	return NewClientMetricsWithRegisterer(namespace, prometheus.DefaultRegisterer, opts...)
+}
+
+// NewClientMetricsWithRegisterer is like NewClientMetrics but registers
+// with reg.
+func NewClientMetricsWithRegisterer(namespace string, reg prometheus.Registerer, opts ...ClientMetricsOption) *ClientMetrics {
+	options := clientMetricsOptions{buckets: prometheus.DefBuckets, maxHosts: defaultMaxHosts}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_requests_total",
+		Help:      "HTTP requests sent through the circuit breaker client, by upstream host, method and status code.",
+	}, []string{"host", "method", "code"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "client_request_duration_seconds",
+		Help:      "Duration of HTTP requests sent through the circuit breaker client.",
+		Buckets:   options.buckets,
+	}, []string{"host", "method"})
+	return &ClientMetrics{
+		requests: registerOrReuse(reg, requests),
+		duration: registerOrReuse(reg, duration),
+		maxHosts: options.maxHosts,
+		hosts:    make(map[string]struct{}),
+	}
+}
+
+// ObserveRequest implements cbhttp.RequestObserver.
+func (m *ClientMetrics) ObserveRequest(host, method string, status int, duration time.Duration) {
+	host = m.guardHost(host)
+	method = guardMethod(method)
+	m.requests.WithLabelValues(host, method, codeLabel(status)).Inc()
+	m.duration.WithLabelValues(host, method).Observe(duration.Seconds())
+}
+
+func (m *ClientMetrics) guardHost(host string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.hosts[host]; ok {
+		return host
+	}
+	if len(m.hosts) >= m.maxHosts {
+		return "other"
+	}
+	m.hosts[host] = struct{}{}
+	return host
+}
+
+func guardMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "other"
+}
+
+// codeLabel renders a status code for the code label; 0 (no response)
+// becomes "error".
+func codeLabel(status int) string {
+	if status == 0 {
+		return "error"
+	}
+	if status < 100 || status > 599 {
+		return "xxx"
+	}
+	return strconv.Itoa(status)
+}
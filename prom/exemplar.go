@@ -0,0 +1,45 @@
+package prom
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// The breaker probes its Metrics collector for this interface at runtime;
+// keep the implementation honest at compile time.
+var _ circuitbreaker.ContextRecorder = (*Metrics)(nil)
+
+// RecordSuccessContext implements circuitbreaker.ContextRecorder: when the
+// request carries a sampled OTel span, the duration observation gets a
+// trace_id exemplar so Grafana can jump from a latency spike straight to
+// an example trace.
+func (m *Metrics) RecordSuccessContext(ctx context.Context, name string, duration time.Duration) {
+	m.requests.WithLabelValues(m.labelValues(name, "success")...).Inc()
+	m.observe(ctx, name, "success", duration)
+}
+
+// RecordFailureContext implements circuitbreaker.ContextRecorder; see
+// RecordSuccessContext.
+func (m *Metrics) RecordFailureContext(ctx context.Context, name string, duration time.Duration) {
+	m.requests.WithLabelValues(m.labelValues(name, "failure")...).Inc()
+	m.observe(ctx, name, "failure", duration)
+}
+
+func (m *Metrics) observe(ctx context.Context, name, outcome string, duration time.Duration) {
+	observer := m.duration.WithLabelValues(m.labelValues(name, outcome)...)
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if spanCtx.IsValid() && spanCtx.IsSampled() {
+		if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(duration.Seconds(), prometheus.Labels{
+				"trace_id": spanCtx.TraceID().String(),
+			})
+			return
+		}
+	}
+	observer.Observe(duration.Seconds())
+}
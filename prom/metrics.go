@@ -0,0 +1,238 @@
+package prom
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Metrics is a circuitbreaker.MetricsCollector backed by Prometheus. Per
+// breaker it exports:
+//
+//	<ns>_requests_total{name, outcome}      — successes and failures
+//	<ns>_rejections_total{name, reason}     — requests rejected by the breaker
+//	<ns>_state_changes_total{name, from, to}
+//	<ns>_request_duration_seconds{name, outcome}
+//
+// Assign one Metrics to Config.Metrics, typically via the registry
+// defaults, so all breakers share the same metric families.
+type Metrics struct {
+	requests     *prometheus.CounterVec
+	rejections   *prometheus.CounterVec
+	stateChanges *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	probes       *prometheus.CounterVec
+	probeResults *prometheus.CounterVec
+	failures     *prometheus.CounterVec
+
+	attrKeys []string // extra label names, from WithAttributeLabels
+
+	mu    sync.RWMutex
+	attrs map[string][]string // per-breaker attribute values, in attrKeys order
+}
+
+// MetricsOption configures Metrics construction.
+type MetricsOption func(*metricsOptions)
+
+type metricsOptions struct {
+	buckets     []float64
+	constLabels prometheus.Labels
+	attrKeys    []string
+}
+
+// WithBuckets sets the latency histogram buckets, in seconds. The default
+// prometheus.DefBuckets are wrong for both sub-millisecond caches and
+// multi-second third-party APIs; pick buckets spanning the guarded
+// dependency's actual latency.
+func WithBuckets(buckets []float64) MetricsOption {
+	return func(o *metricsOptions) { o.buckets = buckets }
+}
+
+// WithConstLabels attaches static labels (service, env, region, ...) to
+// every exported metric.
+func WithConstLabels(labels prometheus.Labels) MetricsOption {
+	return func(o *metricsOptions) { o.constLabels = labels }
+}
+
+// WithAttributeLabels adds the named breaker attributes (see
+// circuitbreaker.Config.Attributes) as labels on every exported metric.
+// Prometheus label sets are fixed per metric family, so the keys must be
+// declared up front here; breakers missing an attribute export an empty
+// label value.
+func WithAttributeLabels(keys ...string) MetricsOption {
+	return func(o *metricsOptions) { o.attrKeys = append(o.attrKeys, keys...) }
+}
+
+// NewMetrics returns a Metrics registering its collectors with the default
+// Prometheus registry under the given namespace (e.g. "circuitbreaker").
+func NewMetrics(namespace string, opts ...MetricsOption) *Metrics {
+	return NewMetricsWithRegisterer(namespace, prometheus.DefaultRegisterer, opts...)
+}
+
+// NewMetricsWithRegisterer is like NewMetrics but registers with reg, for
+// applications that keep their own prometheus.Registry. Constructing
+// Metrics repeatedly against the same registerer is safe: collectors that
+// are already registered are reused instead of panicking.
+func NewMetricsWithRegisterer(namespace string, reg prometheus.Registerer, opts ...MetricsOption) *Metrics {
+	options := metricsOptions{buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	labels := func(base ...string) []string {
+		return append(base, options.attrKeys...)
+	}
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "requests_total",
+		Help:        "Requests attempted through the circuit breaker, by outcome.",
+		ConstLabels: options.constLabels,
+	}, labels("name", "outcome"))
+	rejections := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "rejections_total",
+		Help:        "Requests rejected by the circuit breaker without being attempted, by reason.",
+		ConstLabels: options.constLabels,
+	}, labels("name", "reason"))
+	stateChanges := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "state_changes_total",
+		Help:        "Circuit breaker state transitions.",
+		ConstLabels: options.constLabels,
+	}, labels("name", "from", "to"))
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   namespace,
+		Name:        "request_duration_seconds",
+		Help:        "Duration of requests attempted through the circuit breaker.",
+		Buckets:     options.buckets,
+		ConstLabels: options.constLabels,
+	}, labels("name", "outcome"))
+	probes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "halfopen_probes_total",
+		Help:        "Probe requests admitted while the circuit breaker was half-open.",
+		ConstLabels: options.constLabels,
+	}, labels("name"))
+	probeResults := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "halfopen_probe_results_total",
+		Help:        "Outcomes of half-open probe requests.",
+		ConstLabels: options.constLabels,
+	}, labels("name", "outcome"))
+	failureCategories := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "failures_total",
+		Help:        "Failures recorded by the circuit breaker, by error category.",
+		ConstLabels: options.constLabels,
+	}, labels("name", "category"))
+	return &Metrics{
+		requests:     registerOrReuse(reg, requests),
+		rejections:   registerOrReuse(reg, rejections),
+		stateChanges: registerOrReuse(reg, stateChanges),
+		duration:     registerOrReuse(reg, duration),
+		probes:       registerOrReuse(reg, probes),
+		probeResults: registerOrReuse(reg, probeResults),
+		failures:     registerOrReuse(reg, failureCategories),
+		attrKeys:     options.attrKeys,
+		attrs:        make(map[string][]string),
+	}
+}
+
+// RecordAttributes implements circuitbreaker.AttributeRecorder: it stores
+// the breaker's attribute values for the keys declared via
+// WithAttributeLabels, to be attached to every subsequent sample.
+func (m *Metrics) RecordAttributes(name string, attrs map[string]string) {
+	if len(m.attrKeys) == 0 {
+		return
+	}
+	values := make([]string, len(m.attrKeys))
+	for i, key := range m.attrKeys {
+		values[i] = attrs[key]
+	}
+	m.mu.Lock()
+	m.attrs[name] = values
+	m.mu.Unlock()
+}
+
+// labelValues returns the breaker's name and the given label values,
+// extended with its attribute values in WithAttributeLabels order.
+func (m *Metrics) labelValues(name string, rest ...string) []string {
+	values := append([]string{name}, rest...)
+	if len(m.attrKeys) == 0 {
+		return values
+	}
+	m.mu.RLock()
+	attrs, ok := m.attrs[name]
+	m.mu.RUnlock()
+	if !ok {
+		attrs = make([]string, len(m.attrKeys))
+	}
+	return append(values, attrs...)
+}
+
+// registerOrReuse registers c with reg, returning the collector already
+// registered under the same descriptors if there is one.
+func registerOrReuse[C prometheus.Collector](reg prometheus.Registerer, c C) C {
+	if err := reg.Register(c); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			return already.ExistingCollector.(C)
+		}
+		panic(err)
+	}
+	return c
+}
+
+// RecordSuccess implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordSuccess(name string, duration time.Duration) {
+	labels := m.labelValues(name, "success")
+	m.requests.WithLabelValues(labels...).Inc()
+	m.duration.WithLabelValues(labels...).Observe(duration.Seconds())
+}
+
+// RecordFailure implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordFailure(name string, duration time.Duration) {
+	labels := m.labelValues(name, "failure")
+	m.requests.WithLabelValues(labels...).Inc()
+	m.duration.WithLabelValues(labels...).Observe(duration.Seconds())
+}
+
+// RecordRejection implements circuitbreaker.MetricsCollector. The breaker
+// prefers RecordRejectionReason; this entry point exists for callers that
+// do not know the reason.
+func (m *Metrics) RecordRejection(name string) {
+	m.rejections.WithLabelValues(m.labelValues(name, circuitbreaker.ReasonOpen)...).Inc()
+}
+
+// RecordRejectionReason implements circuitbreaker.RejectionReasonRecorder.
+func (m *Metrics) RecordRejectionReason(name, reason string) {
+	m.rejections.WithLabelValues(m.labelValues(name, reason)...).Inc()
+}
+
+// RecordStateChange implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordStateChange(name string, from, to circuitbreaker.State) {
+	m.stateChanges.WithLabelValues(m.labelValues(name, from.String(), to.String())...).Inc()
+}
+
+// RecordFailureCategory implements
+// circuitbreaker.FailureCategoryRecorder.
+func (m *Metrics) RecordFailureCategory(name, category string) {
+	m.failures.WithLabelValues(m.labelValues(name, category)...).Inc()
+}
+
+// RecordProbeAttempt implements circuitbreaker.ProbeRecorder.
+func (m *Metrics) RecordProbeAttempt(name string) {
+	m.probes.WithLabelValues(m.labelValues(name)...).Inc()
+}
+
+// RecordProbeResult implements circuitbreaker.ProbeRecorder.
+func (m *Metrics) RecordProbeResult(name string, success bool) {
+	outcome := "failure"
+	if success {
+		outcome = "success"
+	}
+	m.probeResults.WithLabelValues(m.labelValues(name, outcome)...).Inc()
+}
@@ -0,0 +1,65 @@
+package prom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+func TestMetricsRecordsLabeledSamples(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithRegisterer("cb", reg)
+
+	m.RecordSuccess("db", 5*time.Millisecond)
+	m.RecordFailure("db", 10*time.Millisecond)
+	m.RecordRejectionReason("db", circuitbreaker.ReasonOpen)
+	m.RecordStateChange("db", circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("db", "success")); got != 1 {
+		t.Fatalf("requests{outcome=success} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("db", "failure")); got != 1 {
+		t.Fatalf("requests{outcome=failure} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.rejections.WithLabelValues("db", circuitbreaker.ReasonOpen)); got != 1 {
+		t.Fatalf("rejections = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.stateChanges.WithLabelValues("db", "closed", "open")); got != 1 {
+		t.Fatalf("state_changes{from=closed,to=open} = %v, want 1", got)
+	}
+}
+
+func TestMetricsReusesRegisteredCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first := NewMetricsWithRegisterer("cb", reg)
+	// A second construction against the same registerer must reuse the
+	// existing collectors instead of panicking.
+	second := NewMetricsWithRegisterer("cb", reg)
+
+	first.RecordRejection("db")
+	second.RecordRejection("db")
+	if got := testutil.ToFloat64(second.rejections.WithLabelValues("db", circuitbreaker.ReasonOpen)); got != 2 {
+		t.Fatalf("rejections = %v, want 2 (both instances share one counter)", got)
+	}
+}
+
+func TestMetricsAttributeLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetricsWithRegisterer("cb", reg, WithAttributeLabels("tier"))
+
+	m.RecordAttributes("db", map[string]string{"tier": "critical"})
+	m.RecordSuccess("db", time.Millisecond)
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("db", "success", "critical")); got != 1 {
+		t.Fatalf("requests{tier=critical} = %v, want 1", got)
+	}
+
+	// A breaker that never reported attributes exports empty label values.
+	m.RecordSuccess("cache", time.Millisecond)
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("cache", "success", "")); got != 1 {
+		t.Fatalf("requests{tier=\"\"} = %v, want 1", got)
+	}
+}
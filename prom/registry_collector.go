@@ -0,0 +1,147 @@
+// Package prom exports circuit breaker metrics to Prometheus.
+package prom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+const (
+	defaultFlapWindow    = 5 * time.Minute
+	defaultFlapThreshold = 3
+)
+
+// RegistryCollector is a prometheus.Collector exposing fleet-wide circuit
+// health for one registry:
+//
+//	circuitbreaker_breakers{state}        — number of breakers per state
+//	circuitbreaker_rejections_total       — rejections summed over all breakers
+//	circuitbreaker_flapping_breakers      — breakers with several recent transitions
+//
+// A breaker counts as flapping when it changed state at least the flap
+// threshold number of times within the flap window.
+type RegistryCollector struct {
+	registry *circuitbreaker.Registry
+
+	breakersDesc   *prometheus.Desc
+	rejectionsDesc *prometheus.Desc
+	flappingDesc   *prometheus.Desc
+
+	flapWindow    time.Duration
+	flapThreshold int
+
+	mu          sync.Mutex
+	transitions map[string][]time.Time
+}
+
+// CollectorOption configures a RegistryCollector.
+type CollectorOption func(*RegistryCollector)
+
+// WithFlapWindow sets the window within which transitions count towards
+// flapping. The default is 5 minutes.
+func WithFlapWindow(window time.Duration) CollectorOption {
+	return func(c *RegistryCollector) { c.flapWindow = window }
+}
+
+// WithFlapThreshold sets how many transitions within the flap window mark a
+// breaker as flapping. The default is 3.
+func WithFlapThreshold(n int) CollectorOption {
+	return func(c *RegistryCollector) { c.flapThreshold = n }
+}
+
+// NewRegistryCollector returns a collector for registry. It registers a
+// state-change listener on the registry to track flapping; create it once
+// per registry.
+func NewRegistryCollector(registry *circuitbreaker.Registry, opts ...CollectorOption) *RegistryCollector {
+	c := &RegistryCollector{
+		registry: registry,
+		breakersDesc: prometheus.NewDesc(
+			"circuitbreaker_breakers",
+			"Number of circuit breakers per state.",
+			[]string{"state"}, nil,
+		),
+		rejectionsDesc: prometheus.NewDesc(
+			"circuitbreaker_rejections_total",
+			"Total requests rejected by any circuit breaker in the registry.",
+			nil, nil,
+		),
+		flappingDesc: prometheus.NewDesc(
+			"circuitbreaker_flapping_breakers",
+			"Number of circuit breakers that changed state repeatedly within the flap window.",
+			nil, nil,
+		),
+		flapWindow:    defaultFlapWindow,
+		flapThreshold: defaultFlapThreshold,
+		transitions:   make(map[string][]time.Time),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	registry.OnStateChange(func(name string, _, _ circuitbreaker.State) {
+		c.recordTransition(name, time.Now())
+	})
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *RegistryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.breakersDesc
+	ch <- c.rejectionsDesc
+	ch <- c.flappingDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *RegistryCollector) Collect(ch chan<- prometheus.Metric) {
+	perState := map[circuitbreaker.State]float64{
+		circuitbreaker.StateClosed:   0,
+		circuitbreaker.StateHalfOpen: 0,
+		circuitbreaker.StateOpen:     0,
+	}
+	var rejections float64
+	for _, b := range c.registry.All() {
+		perState[b.State()]++
+		rejections += float64(b.Rejections())
+	}
+	for state, n := range perState {
+		ch <- prometheus.MustNewConstMetric(c.breakersDesc, prometheus.GaugeValue, n, state.String())
+	}
+	ch <- prometheus.MustNewConstMetric(c.rejectionsDesc, prometheus.CounterValue, rejections)
+	ch <- prometheus.MustNewConstMetric(c.flappingDesc, prometheus.GaugeValue, float64(c.flappingCount(time.Now())))
+}
+
+func (c *RegistryCollector) recordTransition(name string, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transitions[name] = append(c.trim(c.transitions[name], at), at)
+}
+
+func (c *RegistryCollector) flappingCount(now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	flapping := 0
+	for name, times := range c.transitions {
+		times = c.trim(times, now)
+		if len(times) == 0 {
+			delete(c.transitions, name)
+			continue
+		}
+		c.transitions[name] = times
+		if len(times) >= c.flapThreshold {
+			flapping++
+		}
+	}
+	return flapping
+}
+
+// trim drops transitions older than the flap window. Callers hold c.mu.
+func (c *RegistryCollector) trim(times []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-c.flapWindow)
+	for len(times) > 0 && times[0].Before(cutoff) {
+		times = times[1:]
+	}
+	return times
+}
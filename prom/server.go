@@ -0,0 +1,129 @@
+package prom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultMaxPaths = 100
+
+// ServerMetrics exports per-method, per-path telemetry for the cbhttp
+// middleware (it implements cbhttp.MiddlewareObserver):
+//
+//	<ns>_server_requests_total{method, path, code}
+//	<ns>_server_request_duration_seconds{method, path}
+//	<ns>_server_shed_total{method, path, reason}
+//
+// The path label is cardinality-guarded: paths run through the configured
+// normalizer (WithPathNormalizer — point it at the router's route
+// template), and after maxPaths distinct values further paths are
+// reported as "other". Methods and status codes are bucketed as in
+// ClientMetrics.
+type ServerMetrics struct {
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	shed      *prometheus.CounterVec
+	normalize func(method, path string) string
+	maxPaths  int
+
+	mu    sync.Mutex
+	paths map[string]struct{}
+}
+
+// ServerMetricsOption configures ServerMetrics construction.
+type ServerMetricsOption func(*serverMetricsOptions)
+
+type serverMetricsOptions struct {
+	buckets   []float64
+	maxPaths  int
+	normalize func(method, path string) string
+}
+
+// WithServerBuckets sets the latency histogram buckets, in seconds.
+func WithServerBuckets(buckets []float64) ServerMetricsOption {
+	return func(o *serverMetricsOptions) { o.buckets = buckets }
+}
+
+// WithMaxPaths sets how many distinct path label values are exported
+// before further paths aggregate into "other". Defaults to 100.
+func WithMaxPaths(n int) ServerMetricsOption {
+	return func(o *serverMetricsOptions) { o.maxPaths = n }
+}
+
+// WithPathNormalizer maps a raw request path to its metrics label —
+// typically the route template ("/v1/users/{id}") so path parameters
+// don't each become a series. Without one, raw paths are used and only
+// the maxPaths cap bounds cardinality.
+func WithPathNormalizer(fn func(method, path string) string) ServerMetricsOption {
+	return func(o *serverMetricsOptions) { o.normalize = fn }
+}
+
+// NewServerMetrics returns a ServerMetrics registering its collectors
+// with the default Prometheus registry under the given namespace.
+func NewServerMetrics(namespace string, opts ...ServerMetricsOption) *ServerMetrics {
+	return NewServerMetricsWithRegisterer(namespace, prometheus.DefaultRegisterer, opts...)
+}
+
+// NewServerMetricsWithRegisterer is like NewServerMetrics but registers
+// with reg.
+func NewServerMetricsWithRegisterer(namespace string, reg prometheus.Registerer, opts ...ServerMetricsOption) *ServerMetrics {
+	options := serverMetricsOptions{buckets: prometheus.DefBuckets, maxPaths: defaultMaxPaths}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "server_requests_total",
+		Help:      "HTTP requests handled behind the circuit breaker middleware, by method, path and status code.",
+	}, []string{"method", "path", "code"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "server_request_duration_seconds",
+		Help:      "Duration of HTTP requests handled behind the circuit breaker middleware.",
+		Buckets:   options.buckets,
+	}, []string{"method", "path"})
+	shed := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "server_shed_total",
+		Help:      "HTTP requests shed by the circuit breaker middleware before reaching the handler, by rejection reason.",
+	}, []string{"method", "path", "reason"})
+	return &ServerMetrics{
+		requests:  registerOrReuse(reg, requests),
+		duration:  registerOrReuse(reg, duration),
+		shed:      registerOrReuse(reg, shed),
+		normalize: options.normalize,
+		maxPaths:  options.maxPaths,
+		paths:     make(map[string]struct{}),
+	}
+}
+
+// ObserveHandled implements cbhttp.MiddlewareObserver.
+func (m *ServerMetrics) ObserveHandled(method, path string, status int, duration time.Duration) {
+	path = m.guardPath(method, path)
+	method = guardMethod(method)
+	m.requests.WithLabelValues(method, path, codeLabel(status)).Inc()
+	m.duration.WithLabelValues(method, path).Observe(duration.Seconds())
+}
+
+// ObserveShed implements cbhttp.MiddlewareObserver.
+func (m *ServerMetrics) ObserveShed(method, path, reason string) {
+	m.shed.WithLabelValues(guardMethod(method), m.guardPath(method, path), reason).Inc()
+}
+
+func (m *ServerMetrics) guardPath(method, path string) string {
+	if m.normalize != nil {
+		path = m.normalize(method, path)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.paths[path]; ok {
+		return path
+	}
+	if len(m.paths) >= m.maxPaths {
+		return "other"
+	}
+	m.paths[path] = struct{}{}
+	return path
+}
@@ -0,0 +1,118 @@
+package prom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// StateDurationCollector is a prometheus.Collector exporting, per breaker:
+//
+//	circuitbreaker_state_duration_seconds_total{name, state} — cumulative
+//	    seconds spent in each state
+//	circuitbreaker_time_since_transition_seconds{name} — seconds since the
+//	    last state change
+//
+// Together they support alerts like "breaker open for more than 10
+// minutes" without needing the scrape to catch individual transitions.
+type StateDurationCollector struct {
+	registry *circuitbreaker.Registry
+
+	durationDesc *prometheus.Desc
+	sinceDesc    *prometheus.Desc
+
+	mu          sync.Mutex
+	accumulated map[string]map[circuitbreaker.State]float64
+	lastChange  map[string]time.Time
+}
+
+// NewStateDurationCollector returns a collector for registry. It registers
+// a state-change listener on the registry; create it once per registry.
+func NewStateDurationCollector(registry *circuitbreaker.Registry) *StateDurationCollector {
+	c := &StateDurationCollector{
+		registry: registry,
+		durationDesc: prometheus.NewDesc(
+			"circuitbreaker_state_duration_seconds_total",
+			"Cumulative seconds each circuit breaker has spent per state.",
+			[]string{"name", "state"}, nil,
+		),
+		sinceDesc: prometheus.NewDesc(
+			"circuitbreaker_time_since_transition_seconds",
+			"Seconds since the circuit breaker last changed state.",
+			[]string{"name"}, nil,
+		),
+		accumulated: make(map[string]map[circuitbreaker.State]float64),
+		lastChange:  make(map[string]time.Time),
+	}
+	registry.OnStateChange(func(name string, from, _ circuitbreaker.State) {
+		c.recordTransition(name, from, time.Now())
+	})
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *StateDurationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.durationDesc
+	ch <- c.sinceDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *StateDurationCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	// Snapshot the breakers before taking c.mu: State() resolves a due
+	// open→half-open transition and fires the registry's state-change
+	// listeners synchronously — including this collector's, whose
+	// recordTransition takes c.mu. Holding the lock across that call
+	// would deadlock any scrape landing after an OpenTimeout expires.
+	type snapshot struct {
+		name  string
+		state circuitbreaker.State
+		since time.Time
+	}
+	breakers := c.registry.All()
+	snapshots := make([]snapshot, 0, len(breakers))
+	for _, b := range breakers {
+		state := b.State() // before StateSince, so a resolved transition is reflected
+		snapshots = append(snapshots, snapshot{name: b.Name(), state: state, since: b.StateSince()})
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range snapshots {
+		totals := map[circuitbreaker.State]float64{
+			circuitbreaker.StateClosed:   0,
+			circuitbreaker.StateHalfOpen: 0,
+			circuitbreaker.StateOpen:     0,
+		}
+		for state, seconds := range c.accumulated[s.name] {
+			totals[state] = seconds
+		}
+		totals[s.state] += now.Sub(s.since).Seconds()
+		for state, seconds := range totals {
+			ch <- prometheus.MustNewConstMetric(c.durationDesc, prometheus.CounterValue,
+				seconds, s.name, state.String())
+		}
+		ch <- prometheus.MustNewConstMetric(c.sinceDesc, prometheus.GaugeValue,
+			now.Sub(s.since).Seconds(), s.name)
+	}
+}
+
+func (c *StateDurationCollector) recordTransition(name string, from circuitbreaker.State, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last, ok := c.lastChange[name]
+	if !ok {
+		if b, found := c.registry.Lookup(name); found {
+			last = b.StateSince()
+		} else {
+			last = now
+		}
+	}
+	if c.accumulated[name] == nil {
+		c.accumulated[name] = make(map[circuitbreaker.State]float64)
+	}
+	c.accumulated[name][from] += now.Sub(last).Seconds()
+	c.lastChange[name] = now
+}
@@ -0,0 +1,74 @@
+package prom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// WindowCollector is a prometheus.Collector exporting, for every breaker
+// in a registry that is configured with a sliding window, the totals the
+// trip decision is evaluating right now:
+//
+//	circuitbreaker_window_requests{name}
+//	circuitbreaker_window_successes{name}
+//	circuitbreaker_window_failures{name}
+//	circuitbreaker_window_failure_rate{name}
+type WindowCollector struct {
+	registry *circuitbreaker.Registry
+
+	requestsDesc  *prometheus.Desc
+	successesDesc *prometheus.Desc
+	failuresDesc  *prometheus.Desc
+	rateDesc      *prometheus.Desc
+}
+
+// NewWindowCollector returns a collector for registry.
+func NewWindowCollector(registry *circuitbreaker.Registry) *WindowCollector {
+	return &WindowCollector{
+		registry: registry,
+		requestsDesc: prometheus.NewDesc(
+			"circuitbreaker_window_requests",
+			"Requests in the breaker's sliding window.",
+			[]string{"name"}, nil,
+		),
+		successesDesc: prometheus.NewDesc(
+			"circuitbreaker_window_successes",
+			"Successes in the breaker's sliding window.",
+			[]string{"name"}, nil,
+		),
+		failuresDesc: prometheus.NewDesc(
+			"circuitbreaker_window_failures",
+			"Failures in the breaker's sliding window.",
+			[]string{"name"}, nil,
+		),
+		rateDesc: prometheus.NewDesc(
+			"circuitbreaker_window_failure_rate",
+			"Failure rate over the breaker's sliding window.",
+			[]string{"name"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *WindowCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requestsDesc
+	ch <- c.successesDesc
+	ch <- c.failuresDesc
+	ch <- c.rateDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *WindowCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, b := range c.registry.All() {
+		counts, ok := b.WindowCounts()
+		if !ok {
+			continue
+		}
+		name := b.Name()
+		ch <- prometheus.MustNewConstMetric(c.requestsDesc, prometheus.GaugeValue, float64(counts.Requests), name)
+		ch <- prometheus.MustNewConstMetric(c.successesDesc, prometheus.GaugeValue, float64(counts.TotalSuccesses), name)
+		ch <- prometheus.MustNewConstMetric(c.failuresDesc, prometheus.GaugeValue, float64(counts.TotalFailures), name)
+		ch <- prometheus.MustNewConstMetric(c.rateDesc, prometheus.GaugeValue, counts.FailureRate(), name)
+	}
+}
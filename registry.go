@@ -0,0 +1,163 @@
+package circuitbreaker
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sync"
+)
+
+// Registry manages a set of named breakers sharing a default configuration,
+// so callers can do registry.Get("payments") anywhere without wiring each
+// breaker up individually. It is safe for concurrent use.
+type Registry struct {
+	defaults  Config
+	namespace string
+	parent    *Registry
+
+	mu        sync.RWMutex
+	breakers  map[string]*Breaker
+	children  map[string]*Registry
+	templates map[string]Config
+	listeners []func(name string, from, to State)
+
+	shutdownHooks []func(ctx context.Context) error
+	healthRules   []HealthRule
+}
+
+// NewRegistry returns a Registry whose breakers are created from defaults.
+// The Name field of defaults is ignored; each breaker is named by the key
+// passed to Get.
+func NewRegistry(defaults Config) *Registry {
+	return &Registry{
+		defaults: defaults,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Get returns the breaker registered under name, creating it from the
+// registry defaults if it does not exist yet.
+func (r *Registry) Get(name string) *Breaker {
+	r.mu.RLock()
+	b, ok := r.breakers[name]
+	r.mu.RUnlock()
+	if ok {
+		return b
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	cfg := r.defaults
+	cfg.Name = name
+	b = New(r.hook(cfg))
+	r.breakers[name] = b
+	return b
+}
+
+// OnStateChange registers a listener called whenever any breaker created by
+// this registry changes state, after the breaker's own OnStateChange hook.
+// Listeners registered after a breaker was created still receive its
+// transitions.
+func (r *Registry) OnStateChange(fn func(name string, from, to State)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// hook chains the registry's state-change listeners onto cfg's own
+// OnStateChange.
+func (r *Registry) hook(cfg Config) Config {
+	own := cfg.OnStateChange
+	cfg.OnStateChange = func(name string, from, to State) {
+		if own != nil {
+			own(name, from, to)
+		}
+		r.notify(name, from, to)
+	}
+	return cfg
+}
+
+// notify fires this registry's listeners and then bubbles up to the parent
+// namespace, so a listener on the root registry observes every tenant.
+func (r *Registry) notify(name string, from, to State) {
+	r.mu.RLock()
+	listeners := r.listeners
+	r.mu.RUnlock()
+	for _, fn := range listeners {
+		fn(name, from, to)
+	}
+	if r.parent != nil {
+		r.parent.notify(name, from, to)
+	}
+}
+
+// GetWithConfig returns the breaker registered under name, creating it with
+// cfg (not the registry defaults) if it does not exist yet.
+func (r *Registry) GetWithConfig(name string, cfg Config) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	cfg.Name = name
+	b := New(r.hook(cfg))
+	r.breakers[name] = b
+	return b
+}
+
+// Lookup returns the breaker registered under name without creating one.
+func (r *Registry) Lookup(name string) (*Breaker, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.breakers[name]
+	return b, ok
+}
+
+// All returns every breaker currently registered, in no particular order.
+func (r *Registry) All() []*Breaker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		all = append(all, b)
+	}
+	return all
+}
+
+// Find returns the breakers whose names match the glob pattern, e.g.
+// Find("db-*") for every database breaker. The pattern syntax is that of
+// path.Match; a malformed pattern matches nothing. Useful for bulk admin
+// operations and aggregated dashboards.
+func (r *Registry) Find(pattern string) []*Breaker {
+	return r.find(func(name string) bool {
+		ok, err := path.Match(pattern, name)
+		return err == nil && ok
+	})
+}
+
+// FindRegexp returns the breakers whose names match re.
+func (r *Registry) FindRegexp(re *regexp.Regexp) []*Breaker {
+	return r.find(re.MatchString)
+}
+
+func (r *Registry) find(match func(name string) bool) []*Breaker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var matched []*Breaker
+	for name, b := range r.breakers {
+		if match(name) {
+			matched = append(matched, b)
+		}
+	}
+	return matched
+}
+
+// Remove deletes the breaker registered under name, if any.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.breakers, name)
+}
@@ -0,0 +1,64 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistryGetReturnsSameBreaker(t *testing.T) {
+	r := NewRegistry(Config{OpenTimeout: time.Minute})
+	a := r.Get("db")
+	if a != r.Get("db") {
+		t.Fatal("Get returned a different breaker for the same name")
+	}
+	if a.Name() != "db" {
+		t.Fatalf("Name = %q, want db", a.Name())
+	}
+	if a == r.Get("cache") {
+		t.Fatal("distinct names share a breaker")
+	}
+}
+
+func TestRegistryOnStateChange(t *testing.T) {
+	r := NewRegistry(Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+	})
+	var gotName string
+	var gotTo State
+	r.OnStateChange(func(name string, from, to State) {
+		gotName, gotTo = name, to
+	})
+	r.Get("db").Do(func() error { return errFail })
+	if gotName != "db" || gotTo != StateOpen {
+		t.Fatalf("listener saw (%q, %v), want (db, open)", gotName, gotTo)
+	}
+}
+
+func TestRegistryLookupAndRemove(t *testing.T) {
+	r := NewRegistry(Config{})
+	if _, ok := r.Lookup("db"); ok {
+		t.Fatal("Lookup found a breaker that was never created")
+	}
+	r.Get("db")
+	if _, ok := r.Lookup("db"); !ok {
+		t.Fatal("Lookup did not find an existing breaker")
+	}
+	r.Remove("db")
+	if _, ok := r.Lookup("db"); ok {
+		t.Fatal("Lookup found a removed breaker")
+	}
+}
+
+func TestRegistryFind(t *testing.T) {
+	r := NewRegistry(Config{})
+	r.Get("http:api")
+	r.Get("http:auth")
+	r.Get("db")
+	if got := len(r.Find("http:*")); got != 2 {
+		t.Fatalf("Find(http:*) returned %d breakers, want 2", got)
+	}
+	if got := len(r.All()); got != 3 {
+		t.Fatalf("All returned %d breakers, want 3", got)
+	}
+}
@@ -0,0 +1,69 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+)
+
+// ShutdownOption configures Registry.Shutdown.
+type ShutdownOption func(*shutdownOptions)
+
+type shutdownOptions struct {
+	forceOpen bool
+}
+
+// WithForceOpen makes Shutdown force every breaker open, so drain logic
+// still running during shutdown fails fast instead of opening new work
+// against dependencies.
+func WithForceOpen() ShutdownOption {
+	return func(o *shutdownOptions) { o.forceOpen = true }
+}
+
+// AddShutdownHook registers fn to be run by Shutdown. Components that own
+// background goroutines — a Persister, a flag controller, pub/sub
+// listeners — register their stop functions here so one Shutdown call
+// drains everything:
+//
+//	registry.AddShutdownHook(func(context.Context) error { return persister.Stop() })
+func (r *Registry) AddShutdownHook(fn func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shutdownHooks = append(r.shutdownHooks, fn)
+}
+
+// Shutdown runs the registered shutdown hooks, recurses into child
+// namespaces, and — with WithForceOpen — forces every breaker open. Hooks
+// run in registration order; all are attempted even if some fail, and
+// their errors are joined.
+func (r *Registry) Shutdown(ctx context.Context, opts ...ShutdownOption) error {
+	var options shutdownOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return r.shutdown(ctx, options)
+}
+
+func (r *Registry) shutdown(ctx context.Context, options shutdownOptions) error {
+	r.mu.Lock()
+	hooks := r.shutdownHooks
+	r.shutdownHooks = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for _, fn := range hooks {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range r.Namespaces() {
+		if err := c.shutdown(ctx, options); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if options.forceOpen {
+		for _, b := range r.All() {
+			b.ForceOpen()
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql/driver"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// cbConn wraps a driver connection, guarding the operations that hit the
+// database. Optional driver interfaces are only claimed when the
+// underlying connection implements them: the guarded QueryContext /
+// ExecContext return driver.ErrSkip otherwise, so database/sql falls back
+// to its prepared-statement path, whose Prepare is guarded too.
+type cbConn struct {
+	conn    driver.Conn
+	breaker *circuitbreaker.Breaker
+	opts    options
+}
+
+func (c *cbConn) Prepare(query string) (driver.Stmt, error) {
+	return c.prepare(context.Background(), query)
+}
+
+func (c *cbConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.prepare(ctx, query)
+}
+
+func (c *cbConn) prepare(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	err := guard(ctx, c.breaker, c.opts, func() error {
+		var err error
+		if pc, ok := c.conn.(driver.ConnPrepareContext); ok {
+			stmt, err = pc.PrepareContext(ctx, query)
+		} else {
+			stmt, err = c.conn.Prepare(query)
+		}
+		return err
+	})
+	return stmt, err
+}
+
+func (c *cbConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var rows driver.Rows
+	err := guard(ctx, c.breaker, c.opts, func() error {
+		var err error
+		rows, err = qc.QueryContext(ctx, query, args)
+		return err
+	})
+	return rows, err
+}
+
+func (c *cbConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var result driver.Result
+	err := guard(ctx, c.breaker, c.opts, func() error {
+		var err error
+		result, err = ec.ExecContext(ctx, query, args)
+		return err
+	})
+	return result, err
+}
+
+func (c *cbConn) Ping(ctx context.Context) error {
+	p, ok := c.conn.(driver.Pinger)
+	if !ok {
+		return nil
+	}
+	return guard(ctx, c.breaker, c.opts, func() error {
+		return p.Ping(ctx)
+	})
+}
+
+// Begin and BeginTx open transactions locally; the statements inside them
+// go through the guarded paths above.
+func (c *cbConn) Begin() (driver.Tx, error) { return c.conn.Begin() }
+
+func (c *cbConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bt, ok := c.conn.(driver.ConnBeginTx); ok {
+		return bt.BeginTx(ctx, opts)
+	}
+	return c.conn.Begin()
+}
+
+func (c *cbConn) Close() error { return c.conn.Close() }
+
+func (c *cbConn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *cbConn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *cbConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := c.conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
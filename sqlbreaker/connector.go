@@ -0,0 +1,96 @@
+// Package sqlbreaker runs database/sql operations through a circuit
+// breaker at the driver level: wrap the driver's connector and open the
+// DB from it, and every query, exec, prepare and ping is admitted through
+// the breaker. When the database melts down the circuit opens and new
+// operations fail fast with circuitbreaker.ErrOpen instead of piling onto
+// a drained connection pool.
+//
+//	connector, _ := pq.NewConnector(dsn)
+//	db := sql.OpenDB(sqlbreaker.WrapConnector(connector, breaker))
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// ErrSlowQuery is the failure recorded against the breaker when an
+// operation succeeded but exceeded the slow-call threshold; pair it with
+// circuitbreaker.FailureWeight to trip faster on latency collapse than on
+// sporadic errors.
+var ErrSlowQuery = errors.New("sqlbreaker: slow query")
+
+// options carries the wrapper's configuration.
+type options struct {
+	slowThreshold time.Duration
+}
+
+// Option configures the wrapper.
+type Option func(*options)
+
+// WithSlowCallThreshold counts operations slower than d as failures —
+// recorded as ErrSlowQuery — even when they succeed, so a database that
+// is technically up but drowning still trips the breaker.
+func WithSlowCallThreshold(d time.Duration) Option {
+	return func(o *options) { o.slowThreshold = d }
+}
+
+// WrapConnector returns a driver.Connector admitting connection
+// establishment and all operations on its connections through breaker;
+// open the pool with sql.OpenDB on the result.
+func WrapConnector(c driver.Connector, breaker *circuitbreaker.Breaker, opts ...Option) driver.Connector {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &connector{Connector: c, breaker: breaker, opts: o}
+}
+
+type connector struct {
+	driver.Connector
+	breaker *circuitbreaker.Breaker
+	opts    options
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	var conn driver.Conn
+	err := guard(ctx, c.breaker, c.opts, func() error {
+		var err error
+		conn, err = c.Connector.Connect(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cbConn{conn: conn, breaker: c.breaker, opts: c.opts}, nil
+}
+
+func (c *connector) Driver() driver.Driver { return c.Connector.Driver() }
+
+// guard admits one operation through the breaker, times it, and records
+// the outcome; context cancellation is recorded neutrally (the caller
+// gave up, which says nothing about the database's health) and successes
+// over the slow threshold count as ErrSlowQuery.
+func guard(ctx context.Context, breaker *circuitbreaker.Breaker, opts options, op func() error) error {
+	done, err := breaker.AllowContext(ctx)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	opErr := op()
+	switch {
+	case errors.Is(opErr, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		done(nil)
+	case opErr != nil:
+		done(opErr)
+	case opts.slowThreshold > 0 && time.Since(start) > opts.slowThreshold:
+		done(ErrSlowQuery)
+	default:
+		done(nil)
+	}
+	return opErr
+}
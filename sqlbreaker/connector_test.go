@@ -0,0 +1,115 @@
+package sqlbreaker
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+var errDB = errors.New("db down")
+
+// fakeConn implements the optional context interfaces so the guarded
+// paths are exercised; fakeBasicConn implements none of them.
+type fakeConn struct {
+	queryErr error
+	delay    time.Duration
+	queries  int
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, nil }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, nil }
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	c.queries++
+	time.Sleep(c.delay)
+	return nil, c.queryErr
+}
+
+type fakeBasicConn struct{ fakeConn }
+
+type fakeConnector struct{ conn driver.Conn }
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return nil }
+
+func tripAfter(n uint64) *circuitbreaker.Breaker {
+	return circuitbreaker.New(circuitbreaker.Config{
+		OpenTimeout: time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= n
+		},
+	})
+}
+
+func connect(t *testing.T, c driver.Connector) *cbConn {
+	t.Helper()
+	conn, err := c.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return conn.(*cbConn)
+}
+
+func TestQueriesOpenBreakerAndFailFast(t *testing.T) {
+	inner := &fakeConn{queryErr: errDB}
+	breaker := tripAfter(2)
+	conn := connect(t, WrapConnector(&fakeConnector{conn: inner}, breaker))
+
+	for i := 0; i < 2; i++ {
+		if _, err := conn.QueryContext(context.Background(), "SELECT 1", nil); !errors.Is(err, errDB) {
+			t.Fatalf("query %d = %v, want errDB", i, err)
+		}
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+	_, err := conn.QueryContext(context.Background(), "SELECT 1", nil)
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("query while open = %v, want ErrOpen", err)
+	}
+	if inner.queries != 2 {
+		t.Fatalf("driver saw %d queries, want 2 (open circuit must not reach it)", inner.queries)
+	}
+}
+
+func TestSlowQueryCountsAsFailure(t *testing.T) {
+	inner := &fakeConn{delay: 20 * time.Millisecond}
+	breaker := tripAfter(1)
+	conn := connect(t, WrapConnector(&fakeConnector{conn: inner}, breaker,
+		WithSlowCallThreshold(5*time.Millisecond)))
+
+	if _, err := conn.QueryContext(context.Background(), "SELECT 1", nil); err != nil {
+		t.Fatalf("query = %v, want nil (slowness is the breaker's concern, not the caller's)", err)
+	}
+	if got := breaker.State(); got != circuitbreaker.StateOpen {
+		t.Fatalf("state = %v, want open after slow query", got)
+	}
+}
+
+func TestErrSkipRecordsNoOutcome(t *testing.T) {
+	breaker := tripAfter(1)
+	conn := &cbConn{conn: struct{ driver.Conn }{}, breaker: breaker}
+
+	if _, err := conn.QueryContext(context.Background(), "SELECT 1", nil); !errors.Is(err, driver.ErrSkip) {
+		t.Fatalf("query = %v, want ErrSkip", err)
+	}
+	if got := breaker.Counts(); got.Requests != 0 {
+		t.Fatalf("Requests = %d, want 0 (ErrSkip is not an attempt)", got.Requests)
+	}
+}
+
+func TestCanceledQueryIsNeutral(t *testing.T) {
+	inner := &fakeConn{queryErr: context.Canceled}
+	breaker := tripAfter(1)
+	conn := connect(t, WrapConnector(&fakeConnector{conn: inner}, breaker))
+
+	conn.QueryContext(context.Background(), "SELECT 1", nil)
+	if got := breaker.State(); got != circuitbreaker.StateClosed {
+		t.Fatalf("state = %v, want closed (cancellation is neutral)", got)
+	}
+}
@@ -0,0 +1,65 @@
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// State represents the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed means requests flow through normally and outcomes are
+	// counted against the trip condition.
+	StateClosed State = iota
+	// StateHalfOpen means a limited number of probe requests are allowed
+	// through to test whether the dependency has recovered.
+	StateHalfOpen
+	// StateOpen means requests are rejected immediately without reaching
+	// the dependency.
+	StateOpen
+)
+
+// String returns a human-readable name for the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON encodes the state as its string name.
+func (s State) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a state from its string name, accepting the
+// numeric form for compatibility with older snapshots.
+func (s *State) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("circuitbreaker: invalid state %s", data)
+		}
+		*s = State(n)
+		return nil
+	}
+	switch name {
+	case "closed":
+		*s = StateClosed
+	case "half-open":
+		*s = StateHalfOpen
+	case "open":
+		*s = StateOpen
+	default:
+		return fmt.Errorf("circuitbreaker: unknown state %q", name)
+	}
+	return nil
+}
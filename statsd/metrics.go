@@ -0,0 +1,137 @@
+// Package statsd exports circuit breaker metrics over the StatsD protocol
+// with Datadog tag extensions, for teams whose infra is Datadog-native. It
+// speaks plain UDP and needs no client library.
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// Metrics is a circuitbreaker.MetricsCollector emitting StatsD packets.
+// It covers the same signals as the Prometheus implementation:
+//
+//	<prefix>.requests       (count, tags: name, outcome)
+//	<prefix>.rejections     (count, tags: name)
+//	<prefix>.state_changes  (count, tags: name, from, to)
+//	<prefix>.request_duration (timing, tags: name, outcome)
+//
+// Tags use the Datadog "|#key:value" extension; plain StatsD servers that
+// do not understand tags can be fed with WithoutTags.
+type Metrics struct {
+	prefix     string
+	globalTags []string
+	tagged     bool
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Option configures Metrics.
+type Option func(*Metrics)
+
+// WithTags adds constant tags ("env:prod", "service:gateway") to every
+// packet.
+func WithTags(tags ...string) Option {
+	return func(m *Metrics) { m.globalTags = append(m.globalTags, tags...) }
+}
+
+// WithoutTags disables the Datadog tag extension; tag values are folded
+// into the metric name instead ("requests.db.success").
+func WithoutTags() Option {
+	return func(m *Metrics) { m.tagged = false }
+}
+
+// NewMetrics returns a Metrics sending packets to the StatsD server at
+// addr (e.g. "127.0.0.1:8125"). prefix defaults to "circuitbreaker" if
+// empty.
+func NewMetrics(addr, prefix string, opts ...Option) (*Metrics, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == "" {
+		prefix = "circuitbreaker"
+	}
+	m := &Metrics{prefix: prefix, tagged: true, conn: conn}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Close closes the underlying connection.
+func (m *Metrics) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.conn.Close()
+}
+
+// RecordSuccess implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordSuccess(name string, duration time.Duration) {
+	tags := []string{"name:" + name, "outcome:success"}
+	m.send("requests", "1|c", tags)
+	m.send("request_duration", fmt.Sprintf("%.3f|ms", float64(duration)/float64(time.Millisecond)), tags)
+}
+
+// RecordFailure implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordFailure(name string, duration time.Duration) {
+	tags := []string{"name:" + name, "outcome:failure"}
+	m.send("requests", "1|c", tags)
+	m.send("request_duration", fmt.Sprintf("%.3f|ms", float64(duration)/float64(time.Millisecond)), tags)
+}
+
+// RecordRejection implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordRejection(name string) {
+	m.send("rejections", "1|c", []string{"name:" + name})
+}
+
+// RecordStateChange implements circuitbreaker.MetricsCollector.
+func (m *Metrics) RecordStateChange(name string, from, to circuitbreaker.State) {
+	m.send("state_changes", "1|c", []string{
+		"name:" + name, "from:" + from.String(), "to:" + to.String(),
+	})
+}
+
+func (m *Metrics) send(metric, value string, tags []string) {
+	var packet strings.Builder
+	packet.WriteString(m.prefix)
+	packet.WriteByte('.')
+	packet.WriteString(metric)
+	if !m.tagged {
+		for _, tag := range tags {
+			if _, v, ok := strings.Cut(tag, ":"); ok {
+				packet.WriteByte('.')
+				packet.WriteString(sanitize(v))
+			}
+		}
+	}
+	packet.WriteByte(':')
+	packet.WriteString(value)
+	if m.tagged {
+		all := append(append([]string{}, m.globalTags...), tags...)
+		if len(all) > 0 {
+			packet.WriteString("|#")
+			packet.WriteString(strings.Join(all, ","))
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, _ = m.conn.Write([]byte(packet.String())) // UDP send errors are best-effort
+}
+
+// sanitize makes a tag value safe for use inside a metric name.
+func sanitize(v string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '.':
+			return '_'
+		}
+		return r
+	}, v)
+}
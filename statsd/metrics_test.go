@@ -0,0 +1,86 @@
+package statsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	circuitbreaker "github.com/NTbankey1/circuit-breaker"
+)
+
+// listen returns a local UDP sink and a channel of received packets.
+func listen(t *testing.T) (string, chan string) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	packets := make(chan string, 16)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			packets <- string(buf[:n])
+		}
+	}()
+	return conn.LocalAddr().String(), packets
+}
+
+func recv(t *testing.T, packets chan string) string {
+	t.Helper()
+	select {
+	case p := <-packets:
+		return p
+	case <-time.After(2 * time.Second):
+		t.Fatal("no packet within 2s")
+		return ""
+	}
+}
+
+func TestMetricsEmitsTaggedPackets(t *testing.T) {
+	addr, packets := listen(t)
+	m, err := NewMetrics(addr, "cb", WithTags("env:test"))
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+	defer m.Close()
+
+	m.RecordRejection("db")
+	got := recv(t, packets)
+	if !strings.HasPrefix(got, "cb.rejections:1|c") {
+		t.Fatalf("packet = %q", got)
+	}
+	if !strings.Contains(got, "env:test") || !strings.Contains(got, "name:db") {
+		t.Fatalf("packet missing tags: %q", got)
+	}
+
+	m.RecordStateChange("db", circuitbreaker.StateClosed, circuitbreaker.StateOpen)
+	got = recv(t, packets)
+	if !strings.HasPrefix(got, "cb.state_changes:1|c") ||
+		!strings.Contains(got, "from:closed") || !strings.Contains(got, "to:open") {
+		t.Fatalf("packet = %q", got)
+	}
+}
+
+func TestMetricsWithoutTagsFlattensIntoName(t *testing.T) {
+	addr, packets := listen(t)
+	m, err := NewMetrics(addr, "cb", WithoutTags())
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+	defer m.Close()
+
+	m.RecordRejection("db")
+	got := recv(t, packets)
+	if strings.Contains(got, "|#") {
+		t.Fatalf("packet carries tags despite WithoutTags: %q", got)
+	}
+	if !strings.HasPrefix(got, "cb.rejections.db:") {
+		t.Fatalf("packet = %q", got)
+	}
+}
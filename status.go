@@ -0,0 +1,54 @@
+package circuitbreaker
+
+import "time"
+
+// BreakerStatus is the serializable status of one breaker, used uniformly
+// by the JSON status endpoint, the dashboard and any other consumer so
+// they don't each re-derive it.
+type BreakerStatus struct {
+	Name              string             `json:"name"`
+	State             State              `json:"state"`
+	StateSince        time.Time          `json:"state_since"`
+	TimeInState       string             `json:"time_in_state"`
+	Counts            Counts             `json:"counts"`
+	FailureRate       float64            `json:"failure_rate"`
+	Rejections        uint64             `json:"rejections"`
+	Opens             uint64             `json:"opens"`
+	Attributes        map[string]string  `json:"attributes,omitempty"`
+	LastError         string             `json:"last_error,omitempty"`
+	RecentErrors      []FailureSample    `json:"recent_errors,omitempty"`
+	RecentTransitions []TransitionRecord `json:"recent_transitions,omitempty"`
+}
+
+// Status returns the breaker's current status.
+func (b *Breaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	history := make([]TransitionRecord, len(b.history))
+	copy(history, b.history)
+	recent := make([]FailureSample, len(b.failures))
+	copy(recent, b.failures)
+	return BreakerStatus{
+		Name:              b.cfg.Name,
+		State:             b.state,
+		StateSince:        b.stateSince,
+		TimeInState:       now.Sub(b.stateSince).Round(time.Millisecond).String(),
+		Counts:            b.counts,
+		FailureRate:       b.counts.FailureRate(),
+		Rejections:        b.rejections,
+		Opens:             b.opens,
+		Attributes:        b.Attributes(),
+		LastError:         b.lastError,
+		RecentErrors:      recent,
+		RecentTransitions: history,
+	}
+}
+
+// Opens returns how many times the breaker has opened since it was
+// created.
+func (b *Breaker) Opens() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.opens
+}
@@ -0,0 +1,46 @@
+package circuitbreaker
+
+import "fmt"
+
+// Templates let dozens of similar integrations share tuning in one place:
+// define a named Config once, then instantiate breakers from it.
+
+// SetTemplate registers cfg as a named template. The Name field of cfg is
+// ignored; breakers instantiated from the template are named individually.
+// Redefining a template affects only breakers created afterwards.
+func (r *Registry) SetTemplate(name string, cfg Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.templates == nil {
+		r.templates = make(map[string]Config)
+	}
+	r.templates[name] = cfg
+}
+
+// Template returns the named template's Config.
+func (r *Registry) Template(name string) (Config, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cfg, ok := r.templates[name]
+	return cfg, ok
+}
+
+// GetFromTemplate returns the breaker registered under name, creating it
+// from the named template if it does not exist yet. It returns an error if
+// the template is not defined, so a typo in a template name fails loudly
+// rather than silently falling back to defaults.
+func (r *Registry) GetFromTemplate(name, template string) (*Breaker, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.breakers[name]; ok {
+		return b, nil
+	}
+	cfg, ok := r.templates[template]
+	if !ok {
+		return nil, fmt.Errorf("circuitbreaker: template %q is not defined", template)
+	}
+	cfg.Name = name
+	b := New(r.hook(cfg))
+	r.breakers[name] = b
+	return b, nil
+}
@@ -0,0 +1,112 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Topology export renders a registry — its namespaces, breakers and their
+// live states — as Mermaid or Graphviz DOT, for architecture docs and
+// incident whiteboards generated from running state.
+
+// ExportMermaid writes the registry topology as a Mermaid flowchart.
+// Breakers are styled by their current state.
+func (r *Registry) ExportMermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	sb.WriteString("    classDef closed fill:#2e8b57,color:#fff\n")
+	sb.WriteString("    classDef halfopen fill:#e0a800,color:#fff\n")
+	sb.WriteString("    classDef open fill:#c0392b,color:#fff\n")
+	r.writeMermaid(&sb, "registry", "registry", 1)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *Registry) writeMermaid(sb *strings.Builder, id, label string, depth int) {
+	indent := strings.Repeat("    ", depth)
+	fmt.Fprintf(sb, "%ssubgraph %s[%q]\n", indent, id, label)
+	for _, b := range sortedBreakers(r) {
+		nodeID := mermaidID(id + "_" + b.Name())
+		class := strings.ReplaceAll(b.State().String(), "-", "")
+		fmt.Fprintf(sb, "%s    %s[%q]:::%s\n", indent, nodeID, b.Name(), class)
+	}
+	for _, c := range sortedNamespaces(r) {
+		r2 := c
+		r2.writeMermaid(sb, mermaidID(id+"_"+c.NamespaceName()), c.NamespaceName(), depth+1)
+	}
+	fmt.Fprintf(sb, "%send\n", indent)
+}
+
+// ExportDOT writes the registry topology as a Graphviz digraph, with
+// namespaces as clusters and breakers colored by state.
+func (r *Registry) ExportDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph circuitbreakers {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=box, style=filled, fontcolor=white];\n")
+	r.writeDOT(&sb, "registry", "registry", 1)
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (r *Registry) writeDOT(sb *strings.Builder, id, label string, depth int) {
+	indent := strings.Repeat("    ", depth)
+	fmt.Fprintf(sb, "%ssubgraph cluster_%s {\n", indent, mermaidID(id))
+	fmt.Fprintf(sb, "%s    label=%q;\n", indent, label)
+	for _, b := range sortedBreakers(r) {
+		color := map[State]string{
+			StateClosed:   "#2e8b57",
+			StateHalfOpen: "#e0a800",
+			StateOpen:     "#c0392b",
+		}[b.State()]
+		fmt.Fprintf(sb, "%s    %q [label=%q, fillcolor=%q];\n",
+			indent, id+"/"+b.Name(), b.Name()+"\\n"+b.State().String(), color)
+	}
+	for _, c := range sortedNamespaces(r) {
+		c.writeDOT(sb, id+"_"+c.NamespaceName(), c.NamespaceName(), depth+1)
+	}
+	fmt.Fprintf(sb, "%s}\n", indent)
+}
+
+// StateMachineMermaid returns the breaker's state machine as a Mermaid
+// state diagram, annotated with its current state.
+func (b *Breaker) StateMachineMermaid() string {
+	var sb strings.Builder
+	sb.WriteString("stateDiagram-v2\n")
+	sb.WriteString("    [*] --> closed\n")
+	sb.WriteString("    closed --> open: trip\n")
+	sb.WriteString("    open --> half_open: open timeout\n")
+	sb.WriteString("    half_open --> closed: probes succeed\n")
+	sb.WriteString("    half_open --> open: probe fails\n")
+	fmt.Fprintf(&sb, "    note right of %s: %s is here\n",
+		strings.ReplaceAll(b.State().String(), "-", "_"), b.Name())
+	return sb.String()
+}
+
+func mermaidID(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+func sortedBreakers(r *Registry) []*Breaker {
+	breakers := r.All()
+	sort.Slice(breakers, func(i, j int) bool { return breakers[i].Name() < breakers[j].Name() })
+	return breakers
+}
+
+func sortedNamespaces(r *Registry) []*Registry {
+	children := r.Namespaces()
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].NamespaceName() < children[j].NamespaceName()
+	})
+	return children
+}
@@ -0,0 +1,78 @@
+package circuitbreaker
+
+import "time"
+
+const defaultWindowBuckets = 10
+
+// slidingWindow tracks request outcomes over a rolling time window divided
+// into buckets, so the trip decision reflects the recent past rather than
+// everything since the last counter reset.
+type slidingWindow struct {
+	buckets        []Counts
+	bucketDuration time.Duration
+	current        int
+	lastRotate     time.Time
+}
+
+func newSlidingWindow(size time.Duration, bucketCount int, now time.Time) *slidingWindow {
+	return &slidingWindow{
+		buckets:        make([]Counts, bucketCount),
+		bucketDuration: size / time.Duration(bucketCount),
+		lastRotate:     now,
+	}
+}
+
+// rotate advances the window to now, zeroing buckets whose time has
+// passed.
+func (w *slidingWindow) rotate(now time.Time) {
+	elapsed := now.Sub(w.lastRotate)
+	if elapsed < w.bucketDuration {
+		return
+	}
+	steps := int(elapsed / w.bucketDuration)
+	if steps > len(w.buckets) {
+		steps = len(w.buckets)
+	}
+	for i := 0; i < steps; i++ {
+		w.current = (w.current + 1) % len(w.buckets)
+		w.buckets[w.current] = Counts{}
+	}
+	w.lastRotate = now
+}
+
+func (w *slidingWindow) onRequest(now time.Time) {
+	w.rotate(now)
+	w.buckets[w.current].onRequest()
+}
+
+func (w *slidingWindow) onSuccess(now time.Time) {
+	w.rotate(now)
+	w.buckets[w.current].TotalSuccesses++
+}
+
+func (w *slidingWindow) onFailure(now time.Time, weight uint64) {
+	w.rotate(now)
+	w.buckets[w.current].TotalFailures += weight
+}
+
+// aggregate sums the window's buckets. Consecutive counts are not
+// meaningful across buckets and are left zero; the breaker fills them in
+// from its live counts.
+func (w *slidingWindow) aggregate(now time.Time) Counts {
+	w.rotate(now)
+	var total Counts
+	for _, bucket := range w.buckets {
+		total.Requests += bucket.Requests
+		total.TotalSuccesses += bucket.TotalSuccesses
+		total.TotalFailures += bucket.TotalFailures
+	}
+	return total
+}
+
+func (w *slidingWindow) reset(now time.Time) {
+	for i := range w.buckets {
+		w.buckets[i] = Counts{}
+	}
+	w.current = 0
+	w.lastRotate = now
+}
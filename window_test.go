@@ -0,0 +1,78 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlidingWindowAggregates(t *testing.T) {
+	now := time.Now()
+	w := newSlidingWindow(time.Second, 10, now)
+	w.onRequest(now)
+	w.onSuccess(now)
+	w.onRequest(now.Add(50 * time.Millisecond))
+	w.onFailure(now.Add(50*time.Millisecond), 1)
+
+	got := w.aggregate(now.Add(60 * time.Millisecond))
+	want := Counts{Requests: 2, TotalSuccesses: 1, TotalFailures: 1}
+	if got != want {
+		t.Fatalf("aggregate = %+v, want %+v", got, want)
+	}
+}
+
+func TestSlidingWindowRotationExpiresOldOutcomes(t *testing.T) {
+	now := time.Now()
+	w := newSlidingWindow(time.Second, 10, now) // 100ms buckets
+	w.onRequest(now)
+	w.onFailure(now, 1)
+
+	// Half a window later the failure is still visible...
+	if got := w.aggregate(now.Add(500 * time.Millisecond)); got.TotalFailures != 1 {
+		t.Fatalf("aggregate at +500ms = %+v, want the failure retained", got)
+	}
+	// ...but once the full window has passed it has rolled off.
+	if got := w.aggregate(now.Add(1100 * time.Millisecond)); got != (Counts{}) {
+		t.Fatalf("aggregate at +1.1s = %+v, want zero", got)
+	}
+}
+
+func TestSlidingWindowRotationIsIncremental(t *testing.T) {
+	now := time.Now()
+	w := newSlidingWindow(time.Second, 10, now)
+	// One outcome per bucket; each rotation should only expire the
+	// buckets whose time has actually passed.
+	for i := 0; i < 10; i++ {
+		at := now.Add(time.Duration(i) * 100 * time.Millisecond)
+		w.onRequest(at)
+		w.onSuccess(at)
+	}
+	got := w.aggregate(now.Add(950 * time.Millisecond))
+	if got.Requests != 10 {
+		t.Fatalf("Requests = %d, want 10 (nothing expired yet)", got.Requests)
+	}
+	// 400ms later, the four oldest buckets have expired.
+	got = w.aggregate(now.Add(1350 * time.Millisecond))
+	if got.Requests != 6 {
+		t.Fatalf("Requests = %d, want 6 after four buckets expired", got.Requests)
+	}
+}
+
+func TestBreakerWindowCounts(t *testing.T) {
+	cfg := tripAfter(100)
+	cfg.WindowSize = time.Second
+	b := New(cfg)
+	fail(b)
+	succeed(b)
+
+	counts, ok := b.WindowCounts()
+	if !ok {
+		t.Fatal("WindowCounts reported no window on a windowed breaker")
+	}
+	if counts.Requests != 2 || counts.TotalFailures != 1 || counts.TotalSuccesses != 1 {
+		t.Fatalf("WindowCounts = %+v", counts)
+	}
+
+	if _, ok := New(tripAfter(1)).WindowCounts(); ok {
+		t.Fatal("WindowCounts reported a window on an unwindowed breaker")
+	}
+}